@@ -1,12 +1,24 @@
 package assets
 
-import "embed"
+import (
+	"embed"
+	"io/fs"
+)
 
 //go:embed  tab
 var tab embed.FS
 
+func init() {
+	// 内置图标集, 可以用 Register 注册同一命名空间的图标集或 Override 覆盖
+	sub, _ := fs.Sub(tab, "tab")
+	Register("tab", sub)
+}
+
+// Tab 返回 tab 图标集中的资源数据, 不存在时返回 nil 并调用错误处理函数
+//
+//	等同于 Bytes("tab/"+file, Options{}), 应用覆盖的资源优先
 func Tab(file string) []byte {
-	data, err := tab.ReadFile("tab/" + file)
+	data, err := Bytes("tab/"+file, Options{})
 	if err != nil {
 		return nil
 	}
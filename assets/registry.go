@@ -0,0 +1,332 @@
+package assets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound 资源不存在
+var ErrNotFound = errors.New("not found")
+
+// Error 资源查找或解码错误
+type Error struct {
+	Name string // 资源名称
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return "assets: " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Options 查找资源的变体选项
+//
+//	图标集中的文件名: [<主题>/]<名称>[-<大小>][@<倍数>x].png, 例如 dark/close-16@2x.png
+//	查找顺序: 指定主题 -> 默认主题, 指定大小 -> 不区分大小, 指定倍数 -> 较小倍数 -> 1x
+type Options struct {
+	Size  int    // 逻辑大小(像素), 0 不区分大小
+	Scale int    // 缩放倍数, 按 DPI 选择, 0 和 1 为 1x
+	Theme string // 主题, 空字符串使用 SetTheme 设置的当前主题
+}
+
+// Asset 查找到的资源
+type Asset struct {
+	Name  string // 资源名称
+	Path  string // 图标集中的文件路径, 应用覆盖的资源为空
+	Scale int    // 资源实际倍数
+	Key   string // 缓存键, 相同的键对应相同的数据
+	Data  []byte // PNG 数据, 调用方不能修改
+}
+
+// iconSet 一个命名空间下的图标集
+type iconSet struct {
+	namespace string
+	fsys      fs.FS
+}
+
+// lookupKey 查找结果缓存键
+type lookupKey struct {
+	name  string
+	size  int
+	scale int
+	theme string
+}
+
+// registry 资源注册表, 方法可以在任意 goroutine 调用
+type registry struct {
+	lock       sync.RWMutex
+	sets       []iconSet         // 按注册顺序, 后注册的优先
+	overrides  map[string][]byte // 应用覆盖的资源
+	theme      string
+	generation uint64
+	resolved   map[lookupKey]*Asset
+	images     map[string]*image.NRGBA // 解码后的图片, 键为 Asset.Key
+	onError    func(err error)
+}
+
+var reg = &registry{
+	overrides: make(map[string][]byte),
+	resolved:  make(map[lookupKey]*Asset),
+	images:    make(map[string]*image.NRGBA),
+}
+
+// Register 注册图标集, 资源名称为 <namespace>/<文件名>
+//
+//	fsys 可以是 embed.FS(用 fs.Sub 去掉目录前缀) 或 os.DirFS
+//	同一命名空间可以注册多个图标集, 后注册的优先, 缺少的资源在先注册的图标集中查找
+func Register(namespace string, fsys fs.FS) {
+	reg.lock.Lock()
+	reg.sets = append(reg.sets, iconSet{namespace: namespace, fsys: fsys})
+	reg.changed()
+	reg.lock.Unlock()
+}
+
+// RegisterDir 注册磁盘目录作为图标集
+func RegisterDir(namespace, dir string) {
+	Register(namespace, os.DirFS(dir))
+}
+
+// Override 用 PNG 数据覆盖资源的所有变体, 包括内置图标, data 为 nil 时取消覆盖
+//
+//	只影响之后加载资源的控件
+func Override(name string, data []byte) {
+	name = normalizeName(name)
+	reg.lock.Lock()
+	if data == nil {
+		delete(reg.overrides, name)
+	} else {
+		reg.overrides[name] = data
+	}
+	reg.changed()
+	reg.lock.Unlock()
+}
+
+// OverrideFile 用磁盘文件覆盖资源
+func OverrideFile(name, filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return &Error{Name: normalizeName(name), Err: err}
+	}
+	Override(name, data)
+	return nil
+}
+
+// SetTheme 设置当前主题, 空字符串为默认主题
+func SetTheme(theme string) {
+	reg.lock.Lock()
+	reg.theme = theme
+	reg.changed()
+	reg.lock.Unlock()
+}
+
+// Theme 返回当前主题
+func Theme() string {
+	reg.lock.RLock()
+	defer reg.lock.RUnlock()
+	return reg.theme
+}
+
+// SetErrorHandler 设置错误处理函数, 资源不存在或解码失败时调用, nil 不处理
+func SetErrorHandler(fn func(err error)) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	reg.onError = fn
+}
+
+// Generation 返回注册表版本, 注册图标集, 覆盖资源, 切换主题时增加, 用于使外部缓存失效
+func Generation() uint64 {
+	reg.lock.RLock()
+	defer reg.lock.RUnlock()
+	return reg.generation
+}
+
+// Lookup 按名称和变体选项查找资源, 失败时返回 *Error 并调用错误处理函数
+func Lookup(name string, opts Options) (*Asset, error) {
+	asset, err := reg.lookup(name, opts)
+	if err != nil {
+		reg.report(err)
+	}
+	return asset, err
+}
+
+// Has 资源是否存在, 不存在时不调用错误处理函数, 用于可选资源
+func Has(name string, opts Options) bool {
+	_, err := reg.lookup(name, opts)
+	return err == nil
+}
+
+// Bytes 返回资源的 PNG 数据, 调用方不能修改
+func Bytes(name string, opts Options) ([]byte, error) {
+	asset, err := Lookup(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return asset.Data, nil
+}
+
+// Image 返回解码后的资源图片, 同一资源只解码一次, 调用方不能修改
+func Image(name string, opts Options) (*image.NRGBA, error) {
+	asset, err := Lookup(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	img, err := reg.decode(asset)
+	if err != nil {
+		reg.report(err)
+	}
+	return img, err
+}
+
+// changed 注册表变化, 清空缓存, 调用方持有锁
+func (r *registry) changed() {
+	r.generation++
+	r.resolved = make(map[lookupKey]*Asset)
+	r.images = make(map[string]*image.NRGBA)
+}
+
+func (r *registry) report(err error) {
+	r.lock.RLock()
+	fn := r.onError
+	r.lock.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+func (r *registry) lookup(name string, opts Options) (*Asset, error) {
+	name = normalizeName(name)
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	r.lock.RLock()
+	if opts.Theme == "" {
+		opts.Theme = r.theme
+	}
+	key := lookupKey{name: name, size: opts.Size, scale: opts.Scale, theme: opts.Theme}
+	asset, ok := r.resolved[key]
+	generation := r.generation
+	sets := r.sets
+	data, overridden := r.overrides[name]
+	r.lock.RUnlock()
+	if ok {
+		return asset, nil
+	}
+	if overridden {
+		asset = &Asset{Name: name, Scale: 1, Data: data}
+	} else {
+		var err error
+		if asset, err = find(sets, name, opts); err != nil {
+			return nil, err
+		}
+	}
+	asset.Key = fmt.Sprintf("%d:%s:%s@%d", generation, name, asset.Path, asset.Scale)
+	r.lock.Lock()
+	// 查找期间注册表变化时不缓存过期的结果
+	if r.generation == generation {
+		r.resolved[key] = asset
+	}
+	r.lock.Unlock()
+	return asset, nil
+}
+
+// find 在图标集中按变体顺序查找, 后注册的图标集优先
+func find(sets []iconSet, name string, opts Options) (*Asset, error) {
+	namespace, file, ok := strings.Cut(name, "/")
+	if !ok || file == "" {
+		return nil, &Error{Name: name, Err: ErrNotFound}
+	}
+	candidates := variants(file, opts)
+	for i := len(sets) - 1; i >= 0; i-- {
+		if sets[i].namespace != namespace {
+			continue
+		}
+		for _, c := range candidates {
+			data, err := fs.ReadFile(sets[i].fsys, c.path)
+			if err == nil {
+				return &Asset{Name: name, Path: c.path, Scale: c.scale, Data: data}, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, &Error{Name: name, Err: err}
+			}
+		}
+	}
+	return nil, &Error{Name: name, Err: ErrNotFound}
+}
+
+// variant 变体文件路径和倍数
+type variant struct {
+	path  string
+	scale int
+}
+
+// variants 返回按优先顺序排列的变体文件路径
+func variants(file string, opts Options) []variant {
+	themes := []string{opts.Theme}
+	if opts.Theme != "" {
+		themes = append(themes, "")
+	}
+	sizes := []int{opts.Size}
+	if opts.Size > 0 {
+		sizes = append(sizes, 0)
+	}
+	var result []variant
+	for _, theme := range themes {
+		for _, size := range sizes {
+			for scale := opts.Scale; scale >= 1; scale-- {
+				name := file
+				if size > 0 {
+					name += "-" + strconv.Itoa(size)
+				}
+				if scale > 1 {
+					name += "@" + strconv.Itoa(scale) + "x"
+				}
+				result = append(result, variant{path: path.Join(theme, name+".png"), scale: scale})
+			}
+		}
+	}
+	return result
+}
+
+// decode 解码资源图片, 结果按 Asset.Key 缓存
+func (r *registry) decode(asset *Asset) (*image.NRGBA, error) {
+	r.lock.RLock()
+	img, ok := r.images[asset.Key]
+	r.lock.RUnlock()
+	if ok {
+		return img, nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(asset.Data))
+	if err != nil {
+		return nil, &Error{Name: asset.Name, Err: err}
+	}
+	img, ok = src.(*image.NRGBA)
+	if !ok {
+		b := src.Bounds()
+		img = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(img, img.Rect, src, b.Min, draw.Src)
+	}
+	r.lock.Lock()
+	if strings.HasPrefix(asset.Key, strconv.FormatUint(r.generation, 10)+":") {
+		r.images[asset.Key] = img
+	}
+	r.lock.Unlock()
+	return img, nil
+}
+
+// normalizeName 去掉 .png 扩展名, 资源名称不区分是否带扩展名
+func normalizeName(name string) string {
+	return strings.TrimSuffix(name, ".png")
+}
@@ -0,0 +1,245 @@
+package assets
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+)
+
+// useRegistry 测试期间使用空的注册表, 结束后恢复
+func useRegistry(t *testing.T) {
+	t.Helper()
+	saved := reg
+	reg = &registry{
+		overrides: make(map[string][]byte),
+		resolved:  make(map[lookupKey]*Asset),
+		images:    make(map[string]*image.NRGBA),
+	}
+	t.Cleanup(func() {
+		reg = saved
+	})
+}
+
+// file 返回内容为名称的文件, 用于区分查找到的变体
+func file(name string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(name)}
+}
+
+func TestVariants(t *testing.T) {
+	got := variants("close", Options{Size: 16, Scale: 2, Theme: "dark"})
+	want := []variant{
+		{"dark/close-16@2x.png", 2},
+		{"dark/close-16.png", 1},
+		{"dark/close@2x.png", 2},
+		{"dark/close.png", 1},
+		{"close-16@2x.png", 2},
+		{"close-16.png", 1},
+		{"close@2x.png", 2},
+		{"close.png", 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("variants = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("variants[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if got := variants("close", Options{Scale: 1}); len(got) != 1 || got[0].path != "close.png" {
+		t.Errorf("default variants = %v, want [close.png]", got)
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	useRegistry(t)
+	Register("test", fstest.MapFS{
+		"close.png":         file("close"),
+		"close-16.png":      file("close-16"),
+		"close@2x.png":      file("close@2x"),
+		"dark/close.png":    file("dark/close"),
+		"dark/close@3x.png": file("dark/close@3x"),
+		"only-16@2x.png":    file("only-16@2x"),
+	})
+	tests := []struct {
+		name      string
+		opts      Options
+		want      string
+		wantScale int
+	}{
+		{"exact", Options{}, "close", 1},
+		{"size", Options{Size: 16}, "close-16", 1},
+		{"size to unsized", Options{Size: 24}, "close", 1},
+		{"scale", Options{Scale: 2}, "close@2x", 2},
+		{"3x to 2x", Options{Scale: 3}, "close@2x", 2},
+		{"sized 2x to sized 1x", Options{Size: 16, Scale: 2}, "close-16", 1},
+		{"theme", Options{Theme: "dark"}, "dark/close", 1},
+		{"theme scale", Options{Theme: "dark", Scale: 3}, "dark/close@3x", 3},
+		{"theme to default", Options{Theme: "light"}, "close", 1},
+		{"theme size to default", Options{Theme: "dark", Size: 16}, "dark/close", 1},
+	}
+	for _, tt := range tests {
+		asset, err := Lookup("test/close", tt.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(asset.Data) != tt.want || asset.Scale != tt.wantScale {
+			t.Errorf("%s: got %q @%dx, want %q @%dx", tt.name, asset.Data, asset.Scale, tt.want, tt.wantScale)
+		}
+	}
+	// 大小只有 2x 变体时 1x 查找不到
+	if Has("test/only", Options{Size: 16}) {
+		t.Error("1x lookup found 2x only variant")
+	}
+	if !Has("test/only", Options{Size: 16, Scale: 2}) {
+		t.Error("2x lookup missed 2x variant")
+	}
+	// 当前主题
+	SetTheme("dark")
+	if asset, err := Lookup("test/close.png", Options{}); err != nil || string(asset.Data) != "dark/close" {
+		t.Errorf("current theme = %v, %v", asset, err)
+	}
+}
+
+func TestRegisterOrder(t *testing.T) {
+	useRegistry(t)
+	Register("test", fstest.MapFS{"a.png": file("first a"), "b.png": file("first b")})
+	Register("test", fstest.MapFS{"a.png": file("second a")})
+	Register("other", fstest.MapFS{"b.png": file("other b")})
+	tests := []struct {
+		name, want string
+	}{
+		{"test/a", "second a"}, // 后注册的优先
+		{"test/b", "first b"},  // 缺少的资源在先注册的图标集中查找
+		{"other/b", "other b"}, // 命名空间互不影响
+	}
+	for _, tt := range tests {
+		if data, err := Bytes(tt.name, Options{}); err != nil || string(data) != tt.want {
+			t.Errorf("Bytes(%q) = %q, %v, want %q", tt.name, data, err, tt.want)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	useRegistry(t)
+	Register("test", fstest.MapFS{"close.png": file("close"), "close@2x.png": file("close@2x")})
+	Override("test/close.png", []byte("override"))
+	// 覆盖所有变体
+	for _, opts := range []Options{{}, {Scale: 2}, {Size: 16, Theme: "dark"}} {
+		asset, err := Lookup("test/close", opts)
+		if err != nil || string(asset.Data) != "override" || asset.Path != "" {
+			t.Errorf("Lookup(%+v) = %v, %v, want override", opts, asset, err)
+		}
+	}
+	// 覆盖不存在的资源
+	Override("test/missing", []byte("added"))
+	if data, err := Bytes("test/missing", Options{}); err != nil || string(data) != "added" {
+		t.Errorf("override missing = %q, %v", data, err)
+	}
+	Override("test/close", nil)
+	if data, _ := Bytes("test/close", Options{Scale: 2}); string(data) != "close@2x" {
+		t.Errorf("after removing override = %q, want close@2x", data)
+	}
+}
+
+func TestGeneration(t *testing.T) {
+	useRegistry(t)
+	steps := []struct {
+		name string
+		fn   func()
+	}{
+		{"Register", func() { Register("test", fstest.MapFS{"a.png": file("a")}) }},
+		{"Override", func() { Override("test/a", []byte("b")) }},
+		{"remove override", func() { Override("test/a", nil) }},
+		{"SetTheme", func() { SetTheme("dark") }},
+	}
+	for _, step := range steps {
+		before := Generation()
+		asset, _ := Lookup("test/a", Options{})
+		step.fn()
+		if Generation() != before+1 {
+			t.Errorf("%s: generation %d -> %d, want +1", step.name, before, Generation())
+		}
+		// 缓存键带有版本, 变化后重新查找
+		if after, _ := Lookup("test/a", Options{}); asset != nil && after != nil && after.Key == asset.Key {
+			t.Errorf("%s: key %q not invalidated", step.name, after.Key)
+		}
+	}
+	// 查找不改变版本
+	before := Generation()
+	Lookup("test/a", Options{})
+	Has("test/b", Options{})
+	if Generation() != before {
+		t.Error("lookup changed generation")
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	useRegistry(t)
+	Register("test", fstest.MapFS{"bad.png": file("not a png")})
+	var reported []error
+	SetErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+	tests := []struct {
+		name    string
+		lookup  func() error
+		missing bool
+	}{
+		{"Lookup", func() error { _, err := Lookup("test/missing", Options{}); return err }, true},
+		{"Bytes", func() error { _, err := Bytes("test/missing", Options{Scale: 2}); return err }, true},
+		{"no namespace", func() error { _, err := Lookup("missing", Options{}); return err }, true},
+		{"other namespace", func() error { _, err := Lookup("other/bad", Options{}); return err }, true},
+		{"decode", func() error { _, err := Image("test/bad", Options{}); return err }, false},
+	}
+	for _, tt := range tests {
+		reported = nil
+		err := tt.lookup()
+		var assetErr *Error
+		if !errors.As(err, &assetErr) {
+			t.Errorf("%s: err = %v, want *Error", tt.name, err)
+			continue
+		}
+		if errors.Is(err, ErrNotFound) != tt.missing {
+			t.Errorf("%s: errors.Is(ErrNotFound) = %v, want %v", tt.name, !tt.missing, tt.missing)
+		}
+		if len(reported) != 1 || reported[0] != err {
+			t.Errorf("%s: reported %v, want [%v]", tt.name, reported, err)
+		}
+	}
+	// Has 不调用错误处理函数
+	reported = nil
+	if Has("test/missing", Options{}) || len(reported) != 0 {
+		t.Errorf("Has reported %v", reported)
+	}
+}
+
+func TestImage(t *testing.T) {
+	useRegistry(t)
+	var buf bytes.Buffer
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(1, 0, color.NRGBA{R: 255, A: 128})
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	Register("test", fstest.MapFS{"icon.png": &fstest.MapFile{Data: buf.Bytes()}})
+	img, err := Image("test/icon", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.NRGBAAt(1, 0); got != src.NRGBAAt(1, 0) {
+		t.Errorf("pixel = %v, want %v", got, src.NRGBAAt(1, 0))
+	}
+	// 同一资源只解码一次
+	if again, _ := Image("test/icon", Options{}); again != img {
+		t.Error("image decoded twice")
+	}
+	SetTheme("dark")
+	if again, _ := Image("test/icon", Options{}); again == img {
+		t.Error("image cache not invalidated")
+	}
+}
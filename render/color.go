@@ -0,0 +1,267 @@
+package render
+
+import (
+	"image/color"
+	"math"
+)
+
+// 颜色计算: HSL, HSV, OKLCH 转换, 线性 RGB 混合, WCAG 相对亮度和对比度
+//
+//	只使用 RGB 分量, Alpha 忽略, 返回的颜色 Alpha 为 255
+
+// HSL 色相(0-360), 饱和度(0-1), 亮度(0-1)
+type HSL struct {
+	H, S, L float64
+}
+
+// HSV 色相(0-360), 饱和度(0-1), 明度(0-1)
+type HSV struct {
+	H, S, V float64
+}
+
+// OKLCH OKLCH 感知颜色空间, 亮度(0-1), 色度(0-约0.4), 色相(0-360)
+//
+//	相同的亮度差在不同色相上看起来变化相同, 适合做亮化/暗化
+type OKLCH struct {
+	L, C, H float64
+}
+
+// ToHSL 转换颜色为 HSL
+func ToHSL(c color.NRGBA) HSL {
+	r, g, b := toFloat(c)
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	h := hue(r, g, b, max, min)
+	l := (max + min) / 2
+	s := 0.0
+	if d := max - min; d != 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return HSL{H: h, S: s, L: l}
+}
+
+// FromHSL 转换 HSL 为颜色
+func FromHSL(hsl HSL) color.NRGBA {
+	c := (1 - math.Abs(2*hsl.L-1)) * hsl.S
+	r, g, b := hueToRGB(hsl.H, c)
+	m := hsl.L - c/2
+	return fromFloat(r+m, g+m, b+m)
+}
+
+// ToHSV 转换颜色为 HSV
+func ToHSV(c color.NRGBA) HSV {
+	r, g, b := toFloat(c)
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	s := 0.0
+	if max != 0 {
+		s = (max - min) / max
+	}
+	return HSV{H: hue(r, g, b, max, min), S: s, V: max}
+}
+
+// FromHSV 转换 HSV 为颜色
+func FromHSV(hsv HSV) color.NRGBA {
+	c := hsv.V * hsv.S
+	r, g, b := hueToRGB(hsv.H, c)
+	m := hsv.V - c
+	return fromFloat(r+m, g+m, b+m)
+}
+
+// ToOKLCH 转换颜色为 OKLCH
+func ToOKLCH(c color.NRGBA) OKLCH {
+	r, g, b := toFloat(c)
+	l, a, bb := linearToOKLab(srgbToLinear(r), srgbToLinear(g), srgbToLinear(b))
+	h := math.Atan2(bb, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return OKLCH{L: l, C: math.Hypot(a, bb), H: h}
+}
+
+// FromOKLCH 转换 OKLCH 为颜色
+//
+//	超出 sRGB 范围时保持亮度和色相, 降低色度
+func FromOKLCH(lch OKLCH) color.NRGBA {
+	lch.L = clamp01(lch.L)
+	if lch.C < 0 {
+		lch.C = 0
+	}
+	r, g, b, ok := oklchToLinear(lch)
+	if !ok {
+		// 二分查找范围内的最大色度
+		lo, hi := 0.0, lch.C
+		for i := 0; i < 20; i++ {
+			lch.C = (lo + hi) / 2
+			if _, _, _, ok = oklchToLinear(lch); ok {
+				lo = lch.C
+			} else {
+				hi = lch.C
+			}
+		}
+		lch.C = lo
+		r, g, b, _ = oklchToLinear(lch)
+	}
+	return fromFloat(linearToSRGB(r), linearToSRGB(g), linearToSRGB(b))
+}
+
+// LightenPerceptual 在 OKLCH 空间改变亮度, amount 为负数时暗化
+func LightenPerceptual(c color.NRGBA, amount float64) color.NRGBA {
+	lch := ToOKLCH(c)
+	lch.L += amount
+	return FromOKLCH(lch)
+}
+
+// Mix 在线性 RGB 空间混合两种颜色
+//
+//	t: 0.0 返回 a, 1.0 返回 b
+func Mix(a, b color.NRGBA, t float64) color.NRGBA {
+	t = clamp01(t)
+	ar, ag, ab := toFloat(a)
+	br, bg, bb := toFloat(b)
+	mix := func(x, y float64) float64 {
+		return linearToSRGB(srgbToLinear(x)*(1-t) + srgbToLinear(y)*t)
+	}
+	return fromFloat(mix(ar, br), mix(ag, bg), mix(ab, bb))
+}
+
+// RelativeLuminance 返回 WCAG 相对亮度, 0.0(黑) - 1.0(白)
+func RelativeLuminance(c color.NRGBA) float64 {
+	r, g, b := toFloat(c)
+	return 0.2126*srgbToLinear(r) + 0.7152*srgbToLinear(g) + 0.0722*srgbToLinear(b)
+}
+
+// ContrastRatio 返回两种颜色的 WCAG 对比度, 1 - 21
+//
+//	正文文字建议不低于 4.5, 大号文字不低于 3
+func ContrastRatio(a, b color.NRGBA) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Readable 返回在所有背景色上最低对比度最高的文字颜色的下标, 用于渐变背景
+//
+//	candidates 为空时返回 -1
+func Readable(backgrounds, candidates []color.NRGBA) int {
+	best, bestRatio := -1, -1.0
+	for i, candidate := range candidates {
+		ratio := math.MaxFloat64
+		for _, background := range backgrounds {
+			ratio = math.Min(ratio, ContrastRatio(candidate, background))
+		}
+		if ratio > bestRatio {
+			best, bestRatio = i, ratio
+		}
+	}
+	return best
+}
+
+// toFloat 返回 0-1 范围的 RGB 分量
+func toFloat(c color.NRGBA) (r, g, b float64) {
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255
+}
+
+// fromFloat 从 0-1 范围的 RGB 分量创建不透明颜色
+func fromFloat(r, g, b float64) color.NRGBA {
+	return color.NRGBA{
+		R: uint8(math.Round(clamp01(r) * 255)),
+		G: uint8(math.Round(clamp01(g) * 255)),
+		B: uint8(math.Round(clamp01(b) * 255)),
+		A: 255,
+	}
+}
+
+// hue 返回 RGB 的色相, 0-360
+func hue(r, g, b, max, min float64) float64 {
+	d := max - min
+	if d == 0 {
+		return 0
+	}
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// hueToRGB 返回色相和色度对应的 RGB 分量(未加亮度偏移)
+func hueToRGB(h, c float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	switch {
+	case hp < 1:
+		return c, x, 0
+	case hp < 2:
+		return x, c, 0
+	case hp < 3:
+		return 0, c, x
+	case hp < 4:
+		return 0, x, c
+	case hp < 5:
+		return x, 0, c
+	default:
+		return c, 0, x
+	}
+}
+
+// srgbToLinear sRGB 分量转线性
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB 线性分量转 sRGB
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// linearToOKLab 线性 RGB 转 OKLab
+func linearToOKLab(r, g, b float64) (L, A, B float64) {
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	L = 0.2104542553*l + 0.7936177850*m - 0.0040720468*s
+	A = 1.9779984951*l - 2.4285922050*m + 0.4505937099*s
+	B = 0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+	return
+}
+
+// oklchToLinear OKLCH 转线性 RGB, ok 表示是否在 sRGB 范围内
+func oklchToLinear(lch OKLCH) (r, g, b float64, ok bool) {
+	h := lch.H * math.Pi / 180
+	A, B := lch.C*math.Cos(h), lch.C*math.Sin(h)
+	l := lch.L + 0.3963377774*A + 0.2158037573*B
+	m := lch.L - 0.1055613458*A - 0.0638541728*B
+	s := lch.L - 0.0894841775*A - 1.2914855480*B
+	l, m, s = l*l*l, m*m*m, s*s*s
+	r = 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g = -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b = -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	const eps = 1e-6
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return
+}
+
+// clamp01 限制到 0-1
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
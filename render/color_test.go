@@ -0,0 +1,154 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func rgb(r, g, b uint8) color.NRGBA {
+	return color.NRGBA{R: r, G: g, B: b, A: 255}
+}
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestRelativeLuminance(t *testing.T) {
+	tests := []struct {
+		c    color.NRGBA
+		want float64
+	}{
+		{rgb(0, 0, 0), 0},
+		{rgb(255, 255, 255), 1},
+		{rgb(255, 0, 0), 0.2126},
+		{rgb(0, 255, 0), 0.7152},
+		{rgb(0, 0, 255), 0.0722},
+		{rgb(128, 128, 128), 0.2158605},
+	}
+	for _, tt := range tests {
+		if got := RelativeLuminance(tt.c); !near(got, tt.want, 1e-6) {
+			t.Errorf("RelativeLuminance(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	// 参考值来自 WCAG 2.x 对比度公式
+	tests := []struct {
+		a, b color.NRGBA
+		want float64
+	}{
+		{rgb(0, 0, 0), rgb(255, 255, 255), 21},
+		{rgb(255, 255, 255), rgb(0, 0, 0), 21},
+		{rgb(255, 255, 255), rgb(255, 255, 255), 1},
+		{rgb(118, 118, 118), rgb(255, 255, 255), 4.54},
+		{rgb(119, 119, 119), rgb(255, 255, 255), 4.48},
+		{rgb(255, 0, 0), rgb(255, 255, 255), 4.00},
+		{rgb(0, 0, 255), rgb(255, 255, 255), 8.59},
+	}
+	for _, tt := range tests {
+		if got := ContrastRatio(tt.a, tt.b); !near(got, tt.want, 0.005) {
+			t.Errorf("ContrastRatio(%v, %v) = %.3f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestOKLCH(t *testing.T) {
+	// 参考值来自 OKLab 规范 (Björn Ottosson) 的 sRGB 转换
+	tests := []struct {
+		c    color.NRGBA
+		want OKLCH
+	}{
+		{rgb(255, 255, 255), OKLCH{L: 1, C: 0}},
+		{rgb(0, 0, 0), OKLCH{L: 0, C: 0}},
+		{rgb(255, 0, 0), OKLCH{L: 0.62796, C: 0.25768, H: 29.234}},
+		{rgb(0, 255, 0), OKLCH{L: 0.86644, C: 0.29483, H: 142.495}},
+		{rgb(0, 0, 255), OKLCH{L: 0.45201, C: 0.31321, H: 264.052}},
+	}
+	for _, tt := range tests {
+		got := ToOKLCH(tt.c)
+		if !near(got.L, tt.want.L, 1e-4) || !near(got.C, tt.want.C, 1e-4) {
+			t.Errorf("ToOKLCH(%v) = %+v, want %+v", tt.c, got, tt.want)
+		}
+		if tt.want.C != 0 && !near(got.H, tt.want.H, 0.01) {
+			t.Errorf("ToOKLCH(%v).H = %v, want %v", tt.c, got.H, tt.want.H)
+		}
+		if back := FromOKLCH(got); back != tt.c {
+			t.Errorf("FromOKLCH(ToOKLCH(%v)) = %v", tt.c, back)
+		}
+	}
+	// 超出 sRGB 范围时降低色度, 保持亮度
+	out := FromOKLCH(OKLCH{L: 0.7, C: 0.4, H: 150})
+	if got := ToOKLCH(out); !near(got.L, 0.7, 0.01) {
+		t.Errorf("out of gamut lightness = %v, want 0.7", got.L)
+	}
+}
+
+func TestHSLHSV(t *testing.T) {
+	tests := []struct {
+		c   color.NRGBA
+		hsl HSL
+		hsv HSV
+	}{
+		{rgb(255, 0, 0), HSL{0, 1, 0.5}, HSV{0, 1, 1}},
+		{rgb(0, 255, 0), HSL{120, 1, 0.5}, HSV{120, 1, 1}},
+		{rgb(0, 0, 255), HSL{240, 1, 0.5}, HSV{240, 1, 1}},
+		{rgb(255, 255, 255), HSL{0, 0, 1}, HSV{0, 0, 1}},
+		{rgb(128, 0, 128), HSL{300, 1, 0.25098}, HSV{300, 1, 0.50196}},
+		{rgb(255, 128, 0), HSL{30.118, 1, 0.5}, HSV{30.118, 1, 1}},
+	}
+	for _, tt := range tests {
+		hsl := ToHSL(tt.c)
+		if !near(hsl.H, tt.hsl.H, 0.01) || !near(hsl.S, tt.hsl.S, 1e-4) || !near(hsl.L, tt.hsl.L, 1e-4) {
+			t.Errorf("ToHSL(%v) = %+v, want %+v", tt.c, hsl, tt.hsl)
+		}
+		if back := FromHSL(hsl); back != tt.c {
+			t.Errorf("FromHSL(%+v) = %v, want %v", hsl, back, tt.c)
+		}
+		hsv := ToHSV(tt.c)
+		if !near(hsv.H, tt.hsv.H, 0.01) || !near(hsv.S, tt.hsv.S, 1e-4) || !near(hsv.V, tt.hsv.V, 1e-4) {
+			t.Errorf("ToHSV(%v) = %+v, want %+v", tt.c, hsv, tt.hsv)
+		}
+		if back := FromHSV(hsv); back != tt.c {
+			t.Errorf("FromHSV(%+v) = %v, want %v", hsv, back, tt.c)
+		}
+	}
+}
+
+func TestMix(t *testing.T) {
+	black, white := rgb(0, 0, 0), rgb(255, 255, 255)
+	if got := Mix(black, white, 0); got != black {
+		t.Errorf("Mix 0 = %v", got)
+	}
+	if got := Mix(black, white, 1); got != white {
+		t.Errorf("Mix 1 = %v", got)
+	}
+	// 线性空间的一半亮度对应 sRGB 188
+	if got := Mix(black, white, 0.5); got != rgb(188, 188, 188) {
+		t.Errorf("Mix 0.5 = %v, want 188", got)
+	}
+}
+
+func TestReadable(t *testing.T) {
+	black, white := rgb(0, 0, 0), rgb(255, 255, 255)
+	candidates := []color.NRGBA{black, white}
+	tests := []struct {
+		backgrounds []color.NRGBA
+		want        int
+	}{
+		{[]color.NRGBA{rgb(255, 255, 0)}, 0},
+		{[]color.NRGBA{rgb(0, 0, 128)}, 1},
+		{[]color.NRGBA{rgb(100, 100, 100)}, 1},
+		// 渐变背景取最差的对比度
+		{[]color.NRGBA{rgb(255, 255, 255), rgb(100, 100, 100)}, 0},
+	}
+	for _, tt := range tests {
+		if got := Readable(tt.backgrounds, candidates); got != tt.want {
+			t.Errorf("Readable(%v) = %d, want %d", tt.backgrounds, got, tt.want)
+		}
+	}
+	if got := Readable([]color.NRGBA{white}, nil); got != -1 {
+		t.Errorf("Readable without candidates = %d, want -1", got)
+	}
+}
@@ -0,0 +1,191 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Insets 九宫格边距, 四角按原大小绘制, 四边和中间拉伸或平铺
+type Insets struct {
+	Left, Top, Right, Bottom int
+}
+
+// SliceMode 九宫格四边和中间的填充方式
+type SliceMode int
+
+const (
+	SliceStretch SliceMode = iota // 拉伸
+	SliceTile                     // 平铺
+)
+
+// ToNRGBA 转换图像为 NRGBA, 已经是 NRGBA 时直接返回
+func ToNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok {
+		return img
+	}
+	b := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Rect, src, b.Min, draw.Src)
+	return img
+}
+
+// NineSlice 把 src 按九宫格绘制到 dst 的整个区域, dst 原有像素被覆盖
+//
+//	目标小于左右(上下)边距之和时, 两侧按比例缩小
+func NineSlice(dst *image.NRGBA, src *image.NRGBA, insets Insets, mode SliceMode) {
+	dw, dh := dst.Rect.Dx(), dst.Rect.Dy()
+	if src.Rect.Empty() || dw == 0 || dh == 0 {
+		return
+	}
+	srcX, srcY, dstX, dstY := sliceGrid(src, insets, dw, dh)
+	tile := mode == SliceTile
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			sr := image.Rect(srcX[col], srcY[row], srcX[col+1], srcY[row+1]).Add(src.Rect.Min)
+			dr := image.Rect(dstX[col], dstY[row], dstX[col+1], dstY[row+1])
+			// 中间列在水平方向, 中间行在垂直方向平铺
+			drawCell(dst, dr, src, sr, tile && col == 1, tile && row == 1)
+		}
+	}
+}
+
+// NineSliceAt 返回 src 按九宫格绘制到 width x height 时点 (x, y) 的像素, 不需要绘制整个图像
+//
+//	用于按图片透明度做命中测试, 点在范围外时返回透明像素
+func NineSliceAt(src *image.NRGBA, insets Insets, mode SliceMode, width, height, x, y int) color.NRGBA {
+	if src.Rect.Empty() || x < 0 || y < 0 || x >= width || y >= height {
+		return color.NRGBA{}
+	}
+	srcX, srcY, dstX, dstY := sliceGrid(src, insets, width, height)
+	col, row := cellIndex(dstX, x), cellIndex(dstY, y)
+	sr := image.Rect(srcX[col], srcY[row], srcX[col+1], srcY[row+1]).Add(src.Rect.Min)
+	if sr.Empty() {
+		return color.NRGBA{}
+	}
+	tile := mode == SliceTile
+	fx := axis(x-dstX[col], dstX[col+1]-dstX[col], sr.Dx(), tile && col == 1)
+	fy := axis(y-dstY[row], dstY[row+1]-dstY[row], sr.Dy(), tile && row == 1)
+	c := sample(src, sr, fx, fy)
+	return color.NRGBA{R: c[0], G: c[1], B: c[2], A: c[3]}
+}
+
+// sliceGrid 计算源图和目标的九宫格分割线
+func sliceGrid(src *image.NRGBA, insets Insets, dw, dh int) (srcX, srcY, dstX, dstY [4]int) {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	l, r := clampInsets(insets.Left, insets.Right, sw)
+	t, b := clampInsets(insets.Top, insets.Bottom, sh)
+	dl, dr := fitInsets(l, r, dw)
+	dt, db := fitInsets(t, b, dh)
+	srcX = [4]int{0, l, sw - r, sw}
+	srcY = [4]int{0, t, sh - b, sh}
+	dstX = [4]int{0, dl, dw - dr, dw}
+	dstY = [4]int{0, dt, dh - db, dh}
+	return
+}
+
+// cellIndex 返回坐标 d 所在的格子序号
+func cellIndex(lines [4]int, d int) int {
+	for i := 0; i < 2; i++ {
+		if d < lines[i+1] {
+			return i
+		}
+	}
+	return 2
+}
+
+// clampInsets 限制一对边距不超过源图大小
+func clampInsets(a, b, size int) (int, int) {
+	a, b = max(a, 0), max(b, 0)
+	if a+b > size {
+		a = size * a / (a + b)
+		b = size - a
+	}
+	return a, b
+}
+
+// fitInsets 目标小于边距之和时按比例缩小
+func fitInsets(a, b, size int) (int, int) {
+	if a+b <= size {
+		return a, b
+	}
+	a = size * a / (a + b)
+	return a, size - a
+}
+
+// drawCell 把源区域 sr 绘制到目标区域 dr, 非平铺方向双线性拉伸
+func drawCell(dst *image.NRGBA, dr image.Rectangle, src *image.NRGBA, sr image.Rectangle, tileX, tileY bool) {
+	dw, dh, sw, sh := dr.Dx(), dr.Dy(), sr.Dx(), sr.Dy()
+	if dw <= 0 || dh <= 0 || sw <= 0 || sh <= 0 {
+		return
+	}
+	for y := 0; y < dh; y++ {
+		fy := axis(y, dh, sh, tileY)
+		i := dst.PixOffset(dr.Min.X, dr.Min.Y+y)
+		for x := 0; x < dw; x++ {
+			fx := axis(x, dw, sw, tileX)
+			c := sample(src, sr, fx, fy)
+			copy(dst.Pix[i:i+4], c[:])
+			i += 4
+		}
+	}
+}
+
+// axis 目标坐标 d 映射到源区域内的坐标
+func axis(d, dstSize, srcSize int, tile bool) float64 {
+	if tile {
+		return float64(d % srcSize)
+	}
+	if dstSize == srcSize {
+		return float64(d)
+	}
+	return (float64(d)+0.5)*float64(srcSize)/float64(dstSize) - 0.5
+}
+
+// sample 在源区域 sr 内双线性采样, 坐标相对 sr, 不会采样到区域外
+//
+//	使用预乘透明度插值, 避免透明像素的颜色渗入边缘
+func sample(src *image.NRGBA, sr image.Rectangle, fx, fy float64) [4]byte {
+	maxX, maxY := float64(sr.Dx()-1), float64(sr.Dy()-1)
+	fx, fy = math.Max(0, math.Min(fx, maxX)), math.Max(0, math.Min(fy, maxY))
+	x0, y0 := int(fx), int(fy)
+	x1, y1 := min(x0+1, sr.Dx()-1), min(y0+1, sr.Dy()-1)
+	ax, ay := fx-float64(x0), fy-float64(y0)
+	if ax == 0 && ay == 0 {
+		i := src.PixOffset(sr.Min.X+x0, sr.Min.Y+y0)
+		return [4]byte{src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3]}
+	}
+	var r, g, b, a float64
+	add := func(x, y int, w float64) {
+		if w == 0 {
+			return
+		}
+		i := src.PixOffset(sr.Min.X+x, sr.Min.Y+y)
+		pa := float64(src.Pix[i+3]) * w
+		r += float64(src.Pix[i]) * pa
+		g += float64(src.Pix[i+1]) * pa
+		b += float64(src.Pix[i+2]) * pa
+		a += pa
+	}
+	add(x0, y0, (1-ax)*(1-ay))
+	add(x1, y0, ax*(1-ay))
+	add(x0, y1, (1-ax)*ay)
+	add(x1, y1, ax*ay)
+	if a == 0 {
+		return [4]byte{}
+	}
+	return [4]byte{
+		uint8(math.Round(r / a)),
+		uint8(math.Round(g / a)),
+		uint8(math.Round(b / a)),
+		uint8(math.Round(a)),
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
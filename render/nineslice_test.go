@@ -0,0 +1,150 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sliceSource 返回 6x6 的源图, 每个像素的颜色由坐标决定, 边距 2 时中间为 2x2
+func sliceSource() *image.NRGBA {
+	src := image.NewNRGBA(image.Rect(0, 0, 6, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 50), G: uint8(y * 50), B: 100, A: 255})
+		}
+	}
+	return src
+}
+
+func nineSlice(src *image.NRGBA, insets Insets, mode SliceMode, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	NineSlice(dst, src, insets, mode)
+	return dst
+}
+
+func TestNineSlice(t *testing.T) {
+	src := sliceSource()
+	insets := Insets{Left: 2, Top: 2, Right: 2, Bottom: 2}
+	tests := []struct {
+		name   string
+		mode   SliceMode
+		w, h   int
+		x, y   int // 目标坐标
+		sx, sy int // 对应的源图坐标
+	}{
+		// 同样大小时原样复制
+		{"same size", SliceStretch, 6, 6, 3, 4, 3, 4},
+		// 四角按原大小绘制
+		{"left top", SliceStretch, 10, 10, 1, 1, 1, 1},
+		{"right top", SliceStretch, 10, 10, 9, 0, 5, 0},
+		{"right bottom", SliceStretch, 10, 10, 8, 9, 4, 5},
+		{"tile right bottom", SliceTile, 11, 9, 9, 7, 4, 4},
+		// 拉伸: 中间区域两端对应源图中间区域两端
+		{"stretch top start", SliceStretch, 10, 10, 2, 0, 2, 0},
+		{"stretch top end", SliceStretch, 10, 10, 7, 0, 3, 0},
+		{"stretch left end", SliceStretch, 10, 10, 1, 7, 1, 3},
+		// 平铺: 中间区域按源图中间区域重复
+		{"tile top", SliceTile, 10, 10, 4, 0, 2, 0},
+		{"tile top repeat", SliceTile, 10, 10, 5, 1, 3, 1},
+		{"tile left", SliceTile, 10, 10, 0, 6, 0, 2},
+		{"tile center", SliceTile, 10, 10, 7, 6, 3, 2},
+		// 目标小于边距之和: 左(上)边距缩小为 1, 右(下)边距为 2, 没有中间区域
+		{"small right", SliceStretch, 3, 3, 1, 2, 4, 5},
+		{"small corner", SliceStretch, 3, 3, 2, 1, 5, 4},
+		{"small tile", SliceTile, 3, 3, 2, 2, 5, 5},
+	}
+	for _, tt := range tests {
+		dst := nineSlice(src, insets, tt.mode, tt.w, tt.h)
+		if got, want := dst.NRGBAAt(tt.x, tt.y), src.NRGBAAt(tt.sx, tt.sy); got != want {
+			t.Errorf("%s: pixel (%d, %d) = %v, want %v", tt.name, tt.x, tt.y, got, want)
+		}
+	}
+	// 拉伸时中间的像素插值, 平铺时不插值
+	stretch := nineSlice(src, insets, SliceStretch, 10, 10)
+	if got := stretch.NRGBAAt(4, 0).R; got <= 100 || got >= 150 {
+		t.Errorf("stretched edge R = %d, want between 100 and 150", got)
+	}
+	// 缩小的左边距由源图 2 像素拉伸为 1 像素
+	small := nineSlice(src, insets, SliceStretch, 3, 3)
+	if got := small.NRGBAAt(0, 2).R; got <= 0 || got >= 50 {
+		t.Errorf("shrunk inset R = %d, want between 0 and 50", got)
+	}
+	// 边距大于源图时按比例限制在源图内
+	wide := nineSlice(src, Insets{Left: 10, Right: 10}, SliceStretch, 8, 6)
+	if got, want := wide.NRGBAAt(7, 5), src.NRGBAAt(5, 5); got != want {
+		t.Errorf("clamped insets = %v, want %v", got, want)
+	}
+	// 空图不绘制
+	nineSlice(image.NewNRGBA(image.Rectangle{}), insets, SliceStretch, 4, 4)
+	nineSlice(src, insets, SliceStretch, 0, 4)
+}
+
+func TestNineSliceSubImage(t *testing.T) {
+	src := sliceSource()
+	// 源图在大图中, Rect.Min 不为零
+	big := image.NewNRGBA(image.Rect(0, 0, 12, 14))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			big.SetNRGBA(x+3, y+5, src.NRGBAAt(x, y))
+		}
+	}
+	sub := big.SubImage(image.Rect(3, 5, 9, 11)).(*image.NRGBA)
+	insets := Insets{Left: 2, Top: 1, Right: 2, Bottom: 3}
+	for _, mode := range []SliceMode{SliceStretch, SliceTile} {
+		want := nineSlice(src, insets, mode, 11, 9)
+		got := nineSlice(sub, insets, mode, 11, 9)
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 11; x++ {
+				if got.NRGBAAt(x, y) != want.NRGBAAt(x, y) {
+					t.Fatalf("mode %d: pixel (%d, %d) = %v, want %v", mode, x, y, got.NRGBAAt(x, y), want.NRGBAAt(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestNineSliceAt(t *testing.T) {
+	src := sliceSource()
+	// 带透明度的像素检查预乘插值结果一致
+	src.SetNRGBA(2, 2, color.NRGBA{R: 255, A: 0})
+	src.SetNRGBA(3, 3, color.NRGBA{G: 255, A: 60})
+	big := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			big.SetNRGBA(x+4, y+2, src.NRGBAAt(x, y))
+		}
+	}
+	sub := big.SubImage(image.Rect(4, 2, 10, 8)).(*image.NRGBA)
+	tests := []struct {
+		name   string
+		src    *image.NRGBA
+		insets Insets
+		w, h   int
+	}{
+		{"same size", src, Insets{2, 2, 2, 2}, 6, 6},
+		{"larger", src, Insets{2, 2, 2, 2}, 13, 10},
+		{"uneven", src, Insets{1, 2, 3, 1}, 17, 7},
+		{"smaller", src, Insets{2, 2, 2, 2}, 3, 5},
+		{"no insets", src, Insets{}, 9, 4},
+		{"sub image", sub, Insets{2, 1, 2, 2}, 12, 11},
+	}
+	for _, tt := range tests {
+		for _, mode := range []SliceMode{SliceStretch, SliceTile} {
+			dst := nineSlice(tt.src, tt.insets, mode, tt.w, tt.h)
+			for y := 0; y < tt.h; y++ {
+				for x := 0; x < tt.w; x++ {
+					if got, want := NineSliceAt(tt.src, tt.insets, mode, tt.w, tt.h, x, y), dst.NRGBAAt(x, y); got != want {
+						t.Fatalf("%s mode %d: pixel (%d, %d) = %v, want %v", tt.name, mode, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+	// 范围外为透明
+	for _, p := range []image.Point{{-1, 0}, {0, -1}, {6, 0}, {0, 6}} {
+		if got := NineSliceAt(src, Insets{2, 2, 2, 2}, SliceStretch, 6, 6, p.X, p.Y); got != (color.NRGBA{}) {
+			t.Errorf("outside %v = %v, want transparent", p, got)
+		}
+	}
+}
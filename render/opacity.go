@@ -0,0 +1,73 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// Unblend 由同一内容分别绘制在黑色和白色不透明底图上的结果, 恢复内容的颜色和透明度, 结果写入 dst
+//
+//	LCL 在画布上绘制的文字和图标没有透明度, 两张底图的差就是内容透明的部分:
+//	透明度 = 1 - (白底 - 黑底), 颜色 = 黑底 / 透明度
+//	三个分量的差不同时(子像素抗锯齿文字)取平均值, onBlack 和 onWhite 的透明度忽略
+//	dst, onBlack, onWhite 大小必须相同
+func Unblend(dst, onBlack, onWhite *image.NRGBA) {
+	w, h := dst.Rect.Dx(), dst.Rect.Dy()
+	for y := 0; y < h; y++ {
+		d := dst.Pix[y*dst.Stride : y*dst.Stride+w*4]
+		b := onBlack.Pix[y*onBlack.Stride : y*onBlack.Stride+w*4]
+		wh := onWhite.Pix[y*onWhite.Stride : y*onWhite.Stride+w*4]
+		for i := 0; i < len(d); i += 4 {
+			diff := 0
+			for c := 0; c < 3; c++ {
+				if v := int(wh[i+c]) - int(b[i+c]); v > 0 {
+					diff += v
+				}
+			}
+			alpha := 255 - (diff+1)/3
+			if alpha <= 0 {
+				d[i], d[i+1], d[i+2], d[i+3] = 0, 0, 0, 0
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				d[i+c] = uint8(min((int(b[i+c])*255+alpha/2)/alpha, 255))
+			}
+			d[i+3] = uint8(alpha)
+		}
+	}
+}
+
+// Composite 把内容层 src 合成到 dst 上, 再把结果的透明度乘以整体不透明度, 结果写入 dst
+//
+//	coverage: 形状覆盖率, 如 Style.Coverage, 形状外的内容被裁掉, 为 nil 时不裁剪
+//	dst 和 src 大小必须相同
+func Composite(dst, src *image.NRGBA, coverage func(x, y int) float32, opacity uint8) {
+	w, h := dst.Rect.Dx(), dst.Rect.Dy()
+	for y := 0; y < h; y++ {
+		d := dst.Pix[y*dst.Stride : y*dst.Stride+w*4]
+		s := src.Pix[y*src.Stride : y*src.Stride+w*4]
+		for x := 0; x < w; x++ {
+			i := x * 4
+			sa := float32(s[i+3]) / 255
+			if coverage != nil && sa > 0 {
+				sa *= coverage(x, y)
+			}
+			c := over(color.NRGBA{R: d[i], G: d[i+1], B: d[i+2], A: d[i+3]}, color.NRGBA{R: s[i], G: s[i+1], B: s[i+2]}, sa)
+			d[i], d[i+1], d[i+2] = c.R, c.G, c.B
+			d[i+3] = uint8(float32(c.A)*float32(opacity)/255 + 0.5)
+		}
+	}
+}
+
+// over 按透明度 sa 把颜色 src 叠加到 dst 上, 非预乘透明度
+func over(dst, src color.NRGBA, sa float32) color.NRGBA {
+	if sa <= 0 {
+		return dst
+	}
+	da := float32(dst.A) / 255 * (1 - sa)
+	oa := sa + da
+	mix := func(s, d uint8) uint8 {
+		return uint8((float32(s)*sa+float32(d)*da)/oa + 0.5)
+	}
+	return color.NRGBA{R: mix(src.R, dst.R), G: mix(src.G, dst.G), B: mix(src.B, dst.B), A: uint8(oa*255 + 0.5)}
+}
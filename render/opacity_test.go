@@ -0,0 +1,93 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// onBase 把颜色 c 按透明度叠加到不透明底色 base 上, 模拟 LCL 在底图上绘制
+func onBase(c color.NRGBA, base uint8) color.NRGBA {
+	a := int(c.A)
+	blend := func(v uint8) uint8 {
+		return uint8((int(v)*a + int(base)*(255-a) + 127) / 255)
+	}
+	return color.NRGBA{R: blend(c.R), G: blend(c.G), B: blend(c.B), A: 255}
+}
+
+func TestUnblend(t *testing.T) {
+	tests := []color.NRGBA{
+		{},               // 没有内容
+		{R: 255, A: 255}, // 不透明内容
+		{R: 20, G: 120, B: 220, A: 255},
+		{R: 255, G: 255, B: 255, A: 128}, // 抗锯齿边缘
+		{R: 200, G: 40, B: 40, A: 64},
+		{R: 0, G: 0, B: 0, A: 200},    // 黑色文字
+		{R: 90, G: 200, B: 10, A: 30}, // 低透明度
+	}
+	onBlack := image.NewNRGBA(image.Rect(0, 0, len(tests), 1))
+	onWhite := image.NewNRGBA(image.Rect(0, 0, len(tests), 1))
+	for x, c := range tests {
+		onBlack.SetNRGBA(x, 0, onBase(c, 0))
+		onWhite.SetNRGBA(x, 0, onBase(c, 255))
+	}
+	dst := image.NewNRGBA(onBlack.Rect)
+	Unblend(dst, onBlack, onWhite)
+	for x, want := range tests {
+		got := dst.NRGBAAt(x, 0)
+		if absDiff(got.A, want.A) > 1 {
+			t.Errorf("pixel %d alpha = %d, want %d", x, got.A, want.A)
+			continue
+		}
+		if want.A == 0 {
+			continue
+		}
+		// 透明度越低, 颜色的量化误差越大
+		tolerance := uint8(2 + 255/int(want.A))
+		if absDiff(got.R, want.R) > tolerance || absDiff(got.G, want.G) > tolerance || absDiff(got.B, want.B) > tolerance {
+			t.Errorf("pixel %d = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		name     string
+		dst, src color.NRGBA
+		coverage float32
+		opacity  uint8
+		want     color.NRGBA
+	}{
+		{"no content", red, color.NRGBA{}, 1, 255, red},
+		{"opaque content", red, blue, 1, 255, blue},
+		{"half content", black, color.NRGBA{R: 255, G: 255, B: 255, A: 128}, 1, 255, color.NRGBA{R: 128, G: 128, B: 128, A: 255}},
+		{"content on transparent", color.NRGBA{}, color.NRGBA{R: 10, G: 20, B: 30, A: 100}, 1, 255, color.NRGBA{R: 10, G: 20, B: 30, A: 100}},
+		{"outside shape", red, blue, 0, 255, red},
+		{"opacity", red, color.NRGBA{}, 1, 128, color.NRGBA{R: 255, A: 128}},
+		{"opacity with content", color.NRGBA{}, blue, 1, 64, color.NRGBA{B: 255, A: 64}},
+	}
+	for _, tt := range tests {
+		dst := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+		src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+		dst.SetNRGBA(0, 0, tt.dst)
+		src.SetNRGBA(0, 0, tt.src)
+		Composite(dst, src, func(x, y int) float32 { return tt.coverage }, tt.opacity)
+		if got := dst.NRGBAAt(0, 0); got != tt.want {
+			t.Errorf("%s: pixel = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	// 没有覆盖率时不裁剪
+	dst := Render(Style{Start: red, End: red, Alpha: 255}, 2, 2)
+	src := Render(Style{Start: blue, End: blue, Alpha: 255}, 2, 2)
+	Composite(dst, src, nil, 255)
+	if got := dst.NRGBAAt(1, 1); got != blue {
+		t.Errorf("without coverage = %v, want %v", got, blue)
+	}
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,111 @@
+package render
+
+// PixelOrder 32 位像素中各颜色分量在内存中的字节位置
+type PixelOrder struct {
+	R, G, B, A int
+}
+
+var (
+	OrderBGRA = PixelOrder{R: 2, G: 1, B: 0, A: 3} // B, G, R, A (Windows, GTK3)
+	OrderRGBA = PixelOrder{R: 0, G: 1, B: 2, A: 3} // R, G, B, A (GTK2, Qt)
+	OrderARGB = PixelOrder{R: 1, G: 2, B: 3, A: 0} // A, R, G, B (macOS Cocoa)
+)
+
+// Channel 原始图像描述中的一个颜色分量
+type Channel struct {
+	Shift int // 在像素值中的位移
+	Prec  int // 精度(位数)
+}
+
+// OrderOf 根据原始图像描述(LCL TRawImageDescription)计算像素的字节顺序
+//
+//	bitsPerPixel 必须是 32, 每个分量必须是字节对齐的 8 位, 否则返回 false
+//	msbFirst: 像素值按大端字节序存储
+func OrderOf(bitsPerPixel int, msbFirst bool, red, green, blue, alpha Channel) (PixelOrder, bool) {
+	if bitsPerPixel != 32 {
+		return PixelOrder{}, false
+	}
+	var offsets [4]int
+	used := 0
+	for i, c := range [4]Channel{red, green, blue, alpha} {
+		if c.Prec != 8 || c.Shift%8 != 0 || c.Shift < 0 || c.Shift > 24 {
+			return PixelOrder{}, false
+		}
+		offset := c.Shift / 8
+		if msbFirst {
+			offset = 3 - offset
+		}
+		if used&(1<<offset) != 0 {
+			return PixelOrder{}, false
+		}
+		used |= 1 << offset
+		offsets[i] = offset
+	}
+	return PixelOrder{R: offsets[0], G: offsets[1], B: offsets[2], A: offsets[3]}, true
+}
+
+// ConvertRow 把一行 NRGBA 像素按指定字节顺序写入 dst
+//
+//	dst 和 src 长度都必须至少是 width*4
+func ConvertRow(dst, src []byte, width int, order PixelOrder) {
+	n := width * 4
+	dst, src = dst[:n:n], src[:n:n]
+	switch order {
+	case OrderRGBA:
+		copy(dst, src)
+	case OrderBGRA:
+		for i := 0; i < n; i += 4 {
+			dst[i+0] = src[i+2]
+			dst[i+1] = src[i+1]
+			dst[i+2] = src[i+0]
+			dst[i+3] = src[i+3]
+		}
+	case OrderARGB:
+		for i := 0; i < n; i += 4 {
+			dst[i+0] = src[i+3]
+			dst[i+1] = src[i+0]
+			dst[i+2] = src[i+1]
+			dst[i+3] = src[i+2]
+		}
+	default:
+		for i := 0; i < n; i += 4 {
+			dst[i+order.R] = src[i+0]
+			dst[i+order.G] = src[i+1]
+			dst[i+order.B] = src[i+2]
+			dst[i+order.A] = src[i+3]
+		}
+	}
+}
+
+// ParseRow 把一行指定字节顺序的像素读入 NRGBA 缓冲区, ConvertRow 的逆操作
+//
+//	dst 和 src 长度都必须至少是 width*4
+func ParseRow(dst, src []byte, width int, order PixelOrder) {
+	n := width * 4
+	dst, src = dst[:n:n], src[:n:n]
+	switch order {
+	case OrderRGBA:
+		copy(dst, src)
+	case OrderBGRA:
+		for i := 0; i < n; i += 4 {
+			dst[i+0] = src[i+2]
+			dst[i+1] = src[i+1]
+			dst[i+2] = src[i+0]
+			dst[i+3] = src[i+3]
+		}
+	case OrderARGB:
+		for i := 0; i < n; i += 4 {
+			dst[i+0] = src[i+1]
+			dst[i+1] = src[i+2]
+			dst[i+2] = src[i+3]
+			dst[i+3] = src[i+0]
+		}
+	default:
+		for i := 0; i < n; i += 4 {
+			dst[i+0] = src[i+order.R]
+			dst[i+1] = src[i+order.G]
+			dst[i+2] = src[i+order.B]
+			dst[i+3] = src[i+order.A]
+		}
+	}
+}
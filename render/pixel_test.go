@@ -0,0 +1,98 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"testing"
+)
+
+func TestOrderOf(t *testing.T) {
+	c := func(shift int) Channel { return Channel{Shift: shift, Prec: 8} }
+	tests := []struct {
+		name             string
+		bpp              int
+		msbFirst         bool
+		red, green, blue Channel
+		alpha            Channel
+		want             PixelOrder
+		ok               bool
+	}{
+		{"win32 bgra", 32, false, c(16), c(8), c(0), c(24), OrderBGRA, true},
+		{"gtk2 rgba", 32, false, c(0), c(8), c(16), c(24), OrderRGBA, true},
+		{"cocoa argb", 32, true, c(16), c(8), c(0), c(24), OrderARGB, true},
+		{"abgr", 32, false, c(24), c(16), c(8), c(0), PixelOrder{R: 3, G: 2, B: 1, A: 0}, true},
+		{"24 bit", 24, false, c(16), c(8), c(0), c(24), PixelOrder{}, false},
+		{"no alpha", 32, false, c(16), c(8), c(0), Channel{}, PixelOrder{}, false},
+		{"16 bit channel", 32, false, c(16), c(8), c(0), Channel{Shift: 24, Prec: 16}, PixelOrder{}, false},
+		{"unaligned", 32, false, c(12), c(8), c(0), c(24), PixelOrder{}, false},
+		{"overlap", 32, false, c(0), c(0), c(16), c(24), PixelOrder{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := OrderOf(tt.bpp, tt.msbFirst, tt.red, tt.green, tt.blue, tt.alpha)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: OrderOf = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConvertRow(t *testing.T) {
+	src := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	tests := []struct {
+		order PixelOrder
+		want  []byte
+	}{
+		{OrderRGBA, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{OrderBGRA, []byte{3, 2, 1, 4, 7, 6, 5, 8}},
+		{OrderARGB, []byte{4, 1, 2, 3, 8, 5, 6, 7}},
+		{PixelOrder{R: 3, G: 2, B: 1, A: 0}, []byte{4, 3, 2, 1, 8, 7, 6, 5}},
+	}
+	for _, tt := range tests {
+		dst := make([]byte, len(src))
+		ConvertRow(dst, src, 2, tt.order)
+		if !bytes.Equal(dst, tt.want) {
+			t.Errorf("ConvertRow(%v) = %v, want %v", tt.order, dst, tt.want)
+		}
+		back := make([]byte, len(src))
+		ParseRow(back, dst, 2, tt.order)
+		if !bytes.Equal(back, src) {
+			t.Errorf("ParseRow(%v) = %v, want %v", tt.order, back, src)
+		}
+	}
+}
+
+// 上传背景图像: 逐像素按颜色写入与按扫描线转换
+func BenchmarkUpload(b *testing.B) {
+	for _, size := range []image.Point{{120, 40}, {800, 600}} {
+		src := Render(Style{Start: red, End: blue, Alpha: 255, Radius: 8, Corners: CornerAll}, size.X, size.Y)
+		dst := make([]byte, size.X*size.Y*4)
+		name := fmt.Sprintf("%dx%d", size.X, size.Y)
+		b.Run(name+"/pixel", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				uploadPerPixel(dst, src, OrderBGRA)
+			}
+		})
+		b.Run(name+"/scanline", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for y := 0; y < size.Y; y++ {
+					ConvertRow(dst[y*size.X*4:], src.Pix[y*src.Stride:], size.X, OrderBGRA)
+				}
+			}
+		})
+	}
+}
+
+// uploadPerPixel 逐像素读取颜色并写入, 对应原来逐个调用 SetColors 的方式
+func uploadPerPixel(dst []byte, src *image.NRGBA, order PixelOrder) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := src.NRGBAAt(x, y)
+			i := (y*w + x) * 4
+			dst[i+order.R] = c.R
+			dst[i+order.G] = c.G
+			dst[i+order.B] = c.B
+			dst[i+order.A] = c.A
+		}
+	}
+}
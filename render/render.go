@@ -0,0 +1,210 @@
+// Package render 纯 Go 实现的按钮背景光栅化
+//
+// 根据样式描述(渐变颜色, 圆角, 边框, 透明度)生成 image.NRGBA 图像,
+// 不依赖 liblcl, LCL 只负责把结果绘制到控件上.
+// 使用非预乘透明度(NRGBA), 与 LCL 32 位图像的像素格式一致.
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Corners 圆角方向集合
+type Corners uint8
+
+const (
+	CornerLeftTop Corners = 1 << iota
+	CornerRightTop
+	CornerLeftBottom
+	CornerRightBottom
+	CornerNone Corners = 0
+	CornerAll          = CornerLeftTop | CornerRightTop | CornerLeftBottom | CornerRightBottom
+)
+
+// Has 是否包含指定圆角
+func (c Corners) Has(corner Corners) bool {
+	return c&corner != 0
+}
+
+// Edge 单边边框
+type Edge struct {
+	Width int         // 边框宽度, 0 不显示
+	Color color.NRGBA // 边框颜色, Alpha 为 0 时使用背景渐变色
+}
+
+// Border 四边边框
+type Border struct {
+	Left, Top, Right, Bottom Edge
+}
+
+// Style 背景样式描述, 可比较, 可作为缓存键
+type Style struct {
+	Start   color.NRGBA // 垂直渐变起始颜色(顶部), Alpha 忽略
+	End     color.NRGBA // 垂直渐变结束颜色(底部), Alpha 忽略
+	Alpha   uint8       // 整体透明度 0 ~ 255
+	Radius  int         // 圆角半径
+	Corners Corners     // 圆角方向
+	Border  Border      // 边框
+	NoFill  bool        // 不填充背景, 只绘制边框
+}
+
+// 抗锯齿过渡宽度
+const transition = 1.0
+
+// Render 按指定大小生成样式图像
+func Render(s Style, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	Paint(dst, s)
+	return dst
+}
+
+// Paint 把样式绘制到 dst 的整个区域, dst 原有像素被覆盖
+func Paint(dst *image.NRGBA, s Style) {
+	b := dst.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	for y := 0; y < h; y++ {
+		// 当前行的渐变颜色
+		ratio := 0.0
+		if h > 1 {
+			ratio = float64(y) / float64(h-1)
+		}
+		fill := color.NRGBA{
+			R: lerp(s.Start.R, s.End.R, ratio),
+			G: lerp(s.Start.G, s.End.G, ratio),
+			B: lerp(s.Start.B, s.End.B, ratio),
+		}
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+w*4]
+		for x := 0; x < w; x++ {
+			c := s.pixel(fill, x, y, w, h)
+			i := x * 4
+			row[i+0] = c.R
+			row[i+1] = c.G
+			row[i+2] = c.B
+			row[i+3] = c.A
+		}
+	}
+}
+
+// pixel 计算单个像素的颜色和透明度
+func (s Style) pixel(fill color.NRGBA, x, y, w, h int) color.NRGBA {
+	coverage, corner, dist, radius := s.shape(x, y, w, h)
+	c := fill
+	edge, onEdge := s.edgeAt(corner, dist, radius, x, y, w, h)
+	if onEdge && edge.Color.A != 0 {
+		c = edge.Color
+	}
+	if s.NoFill && !onEdge {
+		c.A = 0
+		return c
+	}
+	c.A = uint8(math.Round(float64(s.Alpha) * float64(coverage)))
+	return c
+}
+
+// Coverage 返回点 (x, y) 处形状的覆盖率 [0.0, 1.0], 圆角外为 0
+// 可用于按形状做鼠标命中测试
+func (s Style) Coverage(x, y, width, height int) float32 {
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return 0
+	}
+	coverage, _, _, _ := s.shape(x, y, width, height)
+	return coverage
+}
+
+// shape 计算点 (x, y) 所在的圆角, 到圆心的距离及覆盖率
+// corner 为 CornerNone 时表示不在圆角区域
+func (s Style) shape(x, y, w, h int) (coverage float32, corner Corners, dist float64, radius int) {
+	// 计算实际可用最大半径（不超过尺寸限制）
+	radius = s.Radius
+	if maxRadius := min(w/2, h/2); radius > maxRadius {
+		radius = maxRadius
+	}
+	if radius <= 0 {
+		return 1, CornerNone, 0, 0
+	}
+	var cx, cy int
+	switch {
+	case s.Corners.Has(CornerLeftTop) && x < radius && y < radius:
+		cx, cy, corner = radius, radius, CornerLeftTop
+	case s.Corners.Has(CornerRightTop) && x >= w-radius && y < radius:
+		cx, cy, corner = w-radius-1, radius, CornerRightTop
+	case s.Corners.Has(CornerLeftBottom) && x < radius && y >= h-radius:
+		cx, cy, corner = radius, h-radius-1, CornerLeftBottom
+	case s.Corners.Has(CornerRightBottom) && x >= w-radius && y >= h-radius:
+		cx, cy, corner = w-radius-1, h-radius-1, CornerRightBottom
+	default:
+		return 1, CornerNone, 0, radius
+	}
+	dist = math.Hypot(float64(x-cx), float64(y-cy))
+	// 抗锯齿过渡处理：根据距离决定 alpha 渐变值
+	inner := float64(radius) - transition
+	switch {
+	case dist <= inner:
+		coverage = 1
+	case dist >= float64(radius)+transition:
+		coverage = 0
+	default:
+		coverage = float32(1 - (dist-inner)/(2*transition))
+	}
+	return
+}
+
+// edgeAt 返回点 (x, y) 所在的边框
+// 圆角内的边框是一段圆环, 宽度取相邻边中先启用的一边(左, 上, 右, 下)
+func (s Style) edgeAt(corner Corners, dist float64, radius, x, y, w, h int) (Edge, bool) {
+	b := s.Border
+	if corner != CornerNone {
+		var edge Edge
+		switch corner {
+		case CornerLeftTop:
+			edge = firstEdge(b.Left, b.Top)
+		case CornerRightTop:
+			edge = firstEdge(b.Top, b.Right)
+		case CornerLeftBottom:
+			edge = firstEdge(b.Left, b.Bottom)
+		case CornerRightBottom:
+			edge = firstEdge(b.Right, b.Bottom)
+		}
+		if edge.Width > 0 && dist > float64(radius-edge.Width)-transition/2 {
+			return edge, true
+		}
+		return Edge{}, false
+	}
+	switch {
+	case b.Left.Width > 0 && x < b.Left.Width:
+		return b.Left, true
+	case b.Top.Width > 0 && y < b.Top.Width:
+		return b.Top, true
+	case b.Right.Width > 0 && x >= w-b.Right.Width:
+		return b.Right, true
+	case b.Bottom.Width > 0 && y >= h-b.Bottom.Width:
+		return b.Bottom, true
+	}
+	return Edge{}, false
+}
+
+func firstEdge(edges ...Edge) Edge {
+	for _, e := range edges {
+		if e.Width > 0 {
+			return e
+		}
+	}
+	return Edge{}
+}
+
+// 颜色分量线性插值
+func lerp(a, b uint8, ratio float64) uint8 {
+	return uint8(math.Round(float64(a)*(1-ratio) + float64(b)*ratio))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
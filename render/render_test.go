@@ -0,0 +1,203 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// go test ./render -update 重新生成 testdata 下的基准图像
+var update = flag.Bool("update", false, "update golden images")
+
+var (
+	red   = color.NRGBA{R: 255, A: 255}
+	blue  = color.NRGBA{B: 255, A: 255}
+	black = color.NRGBA{A: 255}
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+func TestPaintSolid(t *testing.T) {
+	img := Render(Style{Start: red, End: red, Alpha: 255}, 8, 4)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			if got := img.NRGBAAt(x, y); got != red {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, red)
+			}
+		}
+	}
+}
+
+func TestPaintEmpty(t *testing.T) {
+	// 大小为 0 时不绘制, 也不越界
+	Paint(image.NewNRGBA(image.Rect(0, 0, 0, 10)), Style{Alpha: 255})
+	Paint(image.NewNRGBA(image.Rect(0, 0, 10, 0)), Style{Alpha: 255})
+}
+
+func TestPaintGradient(t *testing.T) {
+	img := Render(Style{Start: black, End: white, Alpha: 255}, 1, 5)
+	want := []uint8{0, 64, 128, 191, 255}
+	for y, v := range want {
+		if got := img.NRGBAAt(0, y); got.R != v || got.G != v || got.B != v {
+			t.Errorf("row %d = %v, want gray %d", y, got, v)
+		}
+	}
+	// 只有一行时使用起始颜色
+	if got := Render(Style{Start: red, End: blue, Alpha: 255}, 3, 1).NRGBAAt(1, 0); got != red {
+		t.Errorf("single row = %v, want %v", got, red)
+	}
+}
+
+func TestPaintAlpha(t *testing.T) {
+	for _, alpha := range []uint8{0, 1, 128, 254, 255} {
+		img := Render(Style{Start: red, End: red, Alpha: alpha}, 4, 4)
+		if got := img.NRGBAAt(2, 2); got.A != alpha || got.R != 255 {
+			t.Errorf("alpha %d: pixel = %v", alpha, got)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	s := Style{Radius: 10, Corners: CornerLeftTop}
+	tests := []struct {
+		name string
+		x, y int
+		want float32
+	}{
+		{"outside left", -1, 5, 0},
+		{"outside bottom", 5, 40, 0},
+		{"rounded corner", 0, 0, 0},
+		{"center", 20, 20, 1},
+		{"square corner", 39, 0, 1},
+		{"square corner bottom", 0, 39, 1},
+		{"corner inside", 10, 10, 1},
+	}
+	for _, tt := range tests {
+		if got := s.Coverage(tt.x, tt.y, 40, 40); got != tt.want {
+			t.Errorf("%s: Coverage(%d, %d) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+	// 圆角边缘是抗锯齿过渡
+	if got := s.Coverage(3, 3, 40, 40); got <= 0 || got >= 1 {
+		t.Errorf("Coverage on arc = %v, want (0, 1)", got)
+	}
+}
+
+func TestRadiusClamp(t *testing.T) {
+	// 半径超过短边一半时按短边一半计算, 左右两端成半圆
+	s := Style{Radius: 100, Corners: CornerAll}
+	if got := s.Coverage(10, 5, 20, 10); got != 1 {
+		t.Errorf("center coverage = %v, want 1", got)
+	}
+	if got := s.Coverage(0, 0, 20, 10); got != 0 {
+		t.Errorf("corner coverage = %v, want 0", got)
+	}
+}
+
+func TestBorder(t *testing.T) {
+	s := Style{
+		Start: white, End: white, Alpha: 255,
+		Border: Border{
+			Left:   Edge{Width: 1, Color: red},
+			Top:    Edge{Width: 2, Color: blue},
+			Bottom: Edge{Width: 1},
+		},
+	}
+	img := Render(s, 10, 10)
+	tests := []struct {
+		x, y int
+		want color.NRGBA
+	}{
+		{0, 5, red},   // 左边
+		{5, 0, blue},  // 上边
+		{5, 1, blue},  // 上边第二行
+		{5, 2, white}, // 内部
+		{9, 5, white}, // 右边没有边框
+		{5, 9, white}, // 下边框颜色 Alpha 为 0, 使用背景色
+		{0, 0, red},   // 左上角属于先启用的左边
+	}
+	for _, tt := range tests {
+		if got := img.NRGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestNoFill(t *testing.T) {
+	s := Style{Start: white, End: white, Alpha: 255, NoFill: true, Border: Border{Left: Edge{Width: 1, Color: red}}}
+	img := Render(s, 4, 4)
+	if got := img.NRGBAAt(0, 2); got != red {
+		t.Errorf("border pixel = %v, want %v", got, red)
+	}
+	if got := img.NRGBAAt(2, 2); got.A != 0 {
+		t.Errorf("fill pixel = %v, want transparent", got)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name          string
+		style         Style
+		width, height int
+	}{
+		{"gradient", Style{Start: red, End: blue, Alpha: 255}, 48, 24},
+		{"rounded", Style{Start: white, End: white, Alpha: 255, Radius: 8, Corners: CornerAll}, 48, 24},
+		{"corners", Style{Start: red, End: red, Alpha: 255, Radius: 10, Corners: CornerLeftTop | CornerRightBottom}, 48, 24},
+		{"border", Style{
+			Start: white, End: color.NRGBA{R: 200, G: 200, B: 200}, Alpha: 255, Radius: 6, Corners: CornerAll,
+			Border: Border{
+				Left:   Edge{Width: 1, Color: black},
+				Top:    Edge{Width: 1, Color: black},
+				Right:  Edge{Width: 2, Color: red},
+				Bottom: Edge{Width: 2, Color: red},
+			},
+		}, 48, 24},
+		{"alpha", Style{Start: blue, End: red, Alpha: 128, Radius: 12, Corners: CornerAll}, 48, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGolden(t, tt.name, Render(tt.style, tt.width, tt.height))
+		})
+	}
+}
+
+// checkGolden 与 testdata/<name>.png 逐像素比较, -update 时重新生成
+func checkGolden(t *testing.T, name string, img *image.NRGBA) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".png")
+	if *update {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Bounds() != img.Bounds() {
+		t.Fatalf("size = %v, golden %v", img.Bounds(), decoded.Bounds())
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			want := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+			if got := img.NRGBAAt(x, y); got != want {
+				t.Fatalf("pixel (%d, %d) = %v, golden %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -349,5 +349,52 @@ func (m *TMainForm) FormCreate(sender lcl.IObject) {
 		textAlignRight2.SetText("右对齐\n\n换行文本换行文本")
 		textAlignRight2.SetParent(box)
 	}
+	{
+		// 字体图标, 颜色跟随文字颜色并随状态变化
+		glyphBtn := wg.NewButton(m)
+		glyphBtnRect := types.TRect{Left: 255, Top: 250}
+		glyphBtnRect.SetWidth(150)
+		glyphBtnRect.SetHeight(40)
+		glyphBtn.SetBoundsRect(glyphBtnRect)
+		glyphBtn.Font().SetColor(colors.ClWhite)
+		glyphBtn.Font().SetSize(10)
+		glyphBtn.SetRadius(10)
+		glyphBtn.SetText("字体图标")
+		glyphBtn.SetIconFavoriteGlyph(wg.NewIconGlyph("Segoe MDL2 Assets", 0xE734, 12))
+		glyphBtn.SetIconCloseGlyph(wg.NewIconGlyph("Segoe MDL2 Assets", 0xE711, 10))
+		glyphBtn.SetParent(box)
+	}
+	{
+		// 多个动作图标: 固定, 保存(移入按钮时显示), 关闭
+		zoneBtn := wg.NewButton(m)
+		zoneBtnRect := types.TRect{Left: 415, Top: 250}
+		zoneBtnRect.SetWidth(200)
+		zoneBtnRect.SetHeight(40)
+		zoneBtn.SetBoundsRect(zoneBtnRect)
+		zoneBtn.Font().SetColor(colors.ClWhite)
+		zoneBtn.Font().SetSize(10)
+		zoneBtn.SetRadius(10)
+		zoneBtn.SetText("动作图标")
+		pin := zoneBtn.AddIconZone("pin")
+		pin.SetGlyph(wg.NewIconGlyph("Segoe MDL2 Assets", 0xE718, 10))
+		pin.SetHint("固定")
+		pin.SetCursor(types.CrHandPoint)
+		pin.SetOnClick(func(sender lcl.IObject) {
+			fmt.Println("点击 固定")
+		})
+		save := zoneBtn.AddIconZone("save")
+		save.SetGlyph(wg.NewIconGlyph("Segoe MDL2 Assets", 0xE74E, 10))
+		save.SetHint("保存")
+		save.SetVisibility(wg.IvHover)
+		save.SetOnClick(func(sender lcl.IObject) {
+			fmt.Println("点击 保存")
+		})
+		zoneBtn.SetIconCloseGlyph(wg.NewIconGlyph("Segoe MDL2 Assets", 0xE711, 10))
+		zoneBtn.SetCloseHintText("关闭")
+		zoneBtn.SetOnCloseClick(func(sender lcl.IObject) {
+			fmt.Println("点击 关闭")
+		})
+		zoneBtn.SetParent(box)
+	}
 
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/test/util"
+	"github.com/energye/widget/wg"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	util.TestLoadLibPath()
+}
+
+type TMainForm struct {
+	lcl.TEngForm
+}
+
+var MainForm TMainForm
+
+var (
+	wd, _       = os.Getwd()
+	examplePath = filepath.Join(wd, "test", "button")
+)
+
+func main() {
+	lcl.Init(nil, nil)
+	lcl.Application.Initialize()
+	lcl.Application.SetMainFormOnTaskBar(true)
+	lcl.Application.NewForm(&MainForm)
+	lcl.Application.Run()
+}
+
+func (m *TMainForm) FormCreate(sender lcl.IObject) {
+	m.SetCaption("ENERGY 分段控件")
+	m.SetPosition(types.PoScreenCenter)
+	m.SetWidth(800)
+	m.SetHeight(600)
+	m.SetDoubleBuffered(true)
+	m.SetColor(colors.RGBToColor(56, 57, 60))
+
+	// 单选
+	{
+		seg := wg.NewSegmented(m)
+		seg.SetLeft(50)
+		seg.SetTop(50)
+		seg.SetWidth(360)
+		seg.SetHeight(32)
+		seg.AddSegment("日")
+		seg.AddSegment("周")
+		seg.AddSegment("月")
+		seg.AddSegment("年")
+		seg.SetSelected(0, true)
+		seg.SetOnChange(func(sender lcl.IObject) {
+			fmt.Println("单选:", seg.SelectedIndex())
+		})
+		seg.SetParent(m)
+	}
+	// 多选, 带图标
+	{
+		seg := wg.NewSegmented(m)
+		seg.SetLeft(50)
+		seg.SetTop(120)
+		seg.SetWidth(450)
+		seg.SetHeight(36)
+		seg.SetMultiSelect(true)
+		seg.SetRadius(10)
+		seg.SetColors(colors.RGBToColor(70, 72, 80), colors.RGBToColor(46, 160, 67))
+		seg.SetTextColors(colors.Cl3DFace, colors.ClWhite)
+		seg.SetBorderColor(colors.RGBToColor(30, 30, 34))
+		for _, caption := range []string{"粗体", "斜体", "下划线"} {
+			button := seg.AddSegment(caption)
+			button.SetIconFavorite(filepath.Join(examplePath, "resources", "icon.png"))
+		}
+		seg.SetOnChange(func(sender lcl.IObject) {
+			fmt.Println("多选:", seg.SelectedIndexes())
+		})
+		seg.SetParent(m)
+	}
+}
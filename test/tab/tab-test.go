@@ -2,11 +2,14 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"github.com/energye/lcl/lcl"
 	"github.com/energye/lcl/types"
 	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/assets"
 	"github.com/energye/widget/test/util"
 	"github.com/energye/widget/wg"
+	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -64,10 +67,97 @@ func (m *TMainForm) FormCreate(sender lcl.IObject) {
 	tab.SetAlign(types.AlClient)
 	//tab.EnableScrollButton(false)
 	tab.RecalculatePosition()
+	tab.SetOnReorder(func(sender lcl.IObject, page *wg.TPage, oldIndex, newIndex int) {
+		fmt.Println("拖动排序:", page.Button().Caption(), oldIndex, "->", newIndex)
+	})
+	// 页签可以拖出到新窗口, 也可以拖回来
+	tab.SetTearOff(true)
+	tab.SetAcceptDrop(true)
+	tab.SetOnTabWindow(func(sender lcl.IObject, page *wg.TPage, form lcl.IForm, tab *wg.TTab) {
+		form.SetCaption("拖出 - " + page.Button().Caption())
+	})
+	// 页签栏末尾的页签列表, 包含隐藏的页签
+	tab.SetOverflowButton(true)
+	tab.SetOverflowShowHidden(true)
+	// 不能继续滚动时禁用滚动按钮
+	tab.SetScrollButtonMode(wg.SbmAutoDisable)
+	// Ctrl+Tab 按最近使用顺序切换并显示切换窗口, 焦点在页内容中时由窗口转发按键
+	tab.SetMRUOrder(true)
+	tab.SetSwitcher(true)
+	m.SetKeyPreview(true)
+	m.SetOnKeyDown(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		tab.HandleKeyDown(key, shift)
+	})
+	m.SetOnKeyUp(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		tab.HandleKeyUp(key, shift)
+	})
+	// 依次切换页签栏位置: 上, 右, 下, 左
+	position := wg.TpTop
+	switchPosition := wg.NewButton(m)
+	switchPosition.SetLeft(200)
+	switchPosition.SetTop(20)
+	switchPosition.SetRadius(8)
+	switchPosition.SetBorderDirections(0)
+	switchPosition.SetText("切换页签位置")
+	switchPosition.Font().SetColor(colors.ClWhite)
+	switchPosition.SetOnClick(func(sender lcl.IObject) {
+		position = []wg.TTabPosition{wg.TpRight, wg.TpLeft, wg.TpTop, wg.TpBottom}[position]
+		tab.SetRotatedText(position == wg.TpRight)
+		tab.SetTabPosition(position)
+	})
+	switchPosition.SetParent(m)
+	// 多行显示, 当前页所在行靠近内容区
+	tab.SetActiveRowAdjacent(true)
+	multiRow := wg.NewButton(m)
+	multiRow.SetLeft(340)
+	multiRow.SetTop(20)
+	multiRow.SetRadius(8)
+	multiRow.SetBorderDirections(0)
+	multiRow.SetText("多行 / 滚动")
+	multiRow.Font().SetColor(colors.ClWhite)
+	multiRow.SetOnClick(func(sender lcl.IObject) {
+		tab.SetMultiRow(!tab.MultiRow())
+	})
+	multiRow.SetParent(m)
+	// 固定当前页签, 关闭其它页签时保留固定的页签
+	tab.SetOnPinChange(func(sender lcl.IObject, page *wg.TPage, pinned bool) {
+		fmt.Println("固定页签:", page.Button().Caption(), pinned)
+	})
+	pin := wg.NewButton(m)
+	pin.SetLeft(460)
+	pin.SetTop(20)
+	pin.SetRadius(8)
+	pin.SetBorderDirections(0)
+	pin.SetText("固定 / 取消固定")
+	pin.Font().SetColor(colors.ClWhite)
+	pin.SetOnClick(func(sender lcl.IObject) {
+		if page := tab.ActivePage(); page == nil {
+			return
+		} else if page.Pinned() {
+			page.Unpin()
+		} else {
+			page.Pin()
+		}
+	})
+	pin.SetParent(m)
+	closeOthers := wg.NewButton(m)
+	closeOthers.SetLeft(600)
+	closeOthers.SetTop(20)
+	closeOthers.SetRadius(8)
+	closeOthers.SetBorderDirections(0)
+	closeOthers.SetText("关闭其它")
+	closeOthers.Font().SetColor(colors.ClWhite)
+	closeOthers.SetOnClick(func(sender lcl.IObject) {
+		tab.CloseOthers(tab.ActivePage())
+	})
+	closeOthers.SetParent(m)
 
-	iconData, _ := resource.ReadFile("resources/icon.png")
-	closeData, _ := resource.ReadFile("resources/close.png")
-	closeEnterData, _ := resource.ReadFile("resources/close_enter.png")
+	// 示例图标注册为 demo 图标集, 页签按钮共享解码后的图片
+	demo, _ := fs.Sub(resource, "resources")
+	assets.Register("demo", demo)
+	assets.SetErrorHandler(func(err error) {
+		fmt.Println("资源错误:", err)
+	})
 
 	borderSet := func() {
 		pageSize := len(tab.Pages())
@@ -96,9 +186,8 @@ func (m *TMainForm) FormCreate(sender lcl.IObject) {
 		testPanel.SetParent(page)
 		//btn.SetIconFavorite("C:\\app\\workspace\\widget\\test\\tab\\resources\\icon.png")
 		//btn.SetIconClose("C:\\app\\workspace\\widget\\test\\tab\\resources\\close.png")
-		btn.SetIconFavoriteFormBytes(iconData)
-		btn.SetIconCloseFormBytes(closeData)
-		btn.SetIconCloseHighlightFormBytes(closeEnterData)
+		btn.SetIconFavoriteAsset("demo/icon")
+		btn.SetIconCloseAsset("demo/close")
 		testButton := wg.NewButton(page)
 		testButton.SetLeft(20)
 		testButton.SetTop(20)
@@ -138,6 +227,17 @@ func (m *TMainForm) FormCreate(sender lcl.IObject) {
 		addPage(count)
 		count++
 	}
+	// 页签组, 单击组标签折叠或展开, 拖动页签到组标签上或组成员之间加入组
+	tab.SetOnPageGroup(func(sender lcl.IObject, page *wg.TPage, group *wg.TTabGroup) {
+		if group == nil {
+			fmt.Println("离开页签组:", page.Button().Caption())
+		} else {
+			fmt.Println("加入页签组:", page.Button().Caption(), group.Name())
+		}
+	})
+	group := tab.NewGroup("分组", colors.RGBToColor(26, 115, 232))
+	group.AddPage(tab.Pages()[2])
+	group.AddPage(tab.Pages()[3])
 	lcl.RunOnMainThreadAsync(func(id uint32) {
 		tab.RecalculatePosition()
 	})
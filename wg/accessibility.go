@@ -0,0 +1,211 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"strings"
+)
+
+// TAccessibleRole 按钮的可访问角色
+//
+//	LCL 的可访问角色没有页签项和切换按钮, 按钮使用自己的角色, 设置到 LCL 时转换为最接近的角色
+type TAccessibleRole = int32
+
+const (
+	ArButton       TAccessibleRole = iota // 按钮, 默认
+	ArToggleButton                        // 切换按钮, 多选分段
+	ArRadioButton                         // 单选按钮, 单选分段
+	ArTab                                 // 页签, 所属 TTab 为页签列表
+)
+
+// TAccessibleState 按钮的可访问状态
+type TAccessibleState = int32
+
+const (
+	AsDisabled TAccessibleState = iota // 禁用
+	AsPressed                          // 按下
+	AsChecked                          // 选中
+	AsSelected                         // 页签已选择
+)
+
+// TAccessibleStates 可访问状态集合
+type TAccessibleStates = types.TSet
+
+// AccessibleStateNames 可访问状态的文本, 状态变化时写入 LCL 的 AccessibleValue, 屏幕阅读器朗读
+//
+//	可以在创建控件前设置为界面语言
+var AccessibleStateNames = [...]string{
+	AsDisabled: "disabled",
+	AsPressed:  "pressed",
+	AsChecked:  "checked",
+	AsSelected: "selected",
+}
+
+// AccessiblePress 按钮的默认可访问动作, 与点击按钮相同, 其它动作为动作图标区域的名称
+const AccessiblePress = "press"
+
+// AccessibleStates 返回按钮当前的可访问状态
+func (m *TButton) AccessibleStates() TAccessibleStates {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	states := types.NewSet()
+	if m.isDisable {
+		states = states.Include(AsDisabled)
+	}
+	if m.buttonState == BsDown {
+		states = states.Include(AsPressed)
+	}
+	if m.checked {
+		states = states.Include(AsChecked)
+	}
+	if m.selected {
+		states = states.Include(AsSelected)
+	}
+	return states
+}
+
+// AccessibleActions 返回按钮可以执行的可访问动作, 禁用时为空
+//
+//	AccessiblePress 和显示的动作图标区域名称, 例如 CloseZone
+func (m *TButton) AccessibleActions() []string {
+	if m.Disable() {
+		return nil
+	}
+	actions := []string{AccessiblePress}
+	for _, zone := range m.IconZones() {
+		if zone.Visible() {
+			actions = append(actions, zone.Name())
+		}
+	}
+	return actions
+}
+
+// DoAccessibleAction 执行可访问动作, 供辅助技术调用, 动作不可用时返回 false
+//
+//	AccessiblePress 按 LCL 点击规则触发 OnClick 或绑定的动作, 区域名称触发区域的点击事件
+func (m *TButton) DoAccessibleAction(name string) bool {
+	if m.Disable() {
+		return false
+	}
+	if name == AccessiblePress {
+		runOnMainThread(func() {
+			if m.IsValid() && !m.Disable() {
+				m.Click()
+			}
+		})
+		return true
+	}
+	zone := m.IconZone(name)
+	if zone == nil || !zone.Visible() {
+		return false
+	}
+	m.lock.RLock()
+	fn := zone.onClick
+	m.lock.RUnlock()
+	if fn != nil {
+		runOnMainThread(func() {
+			fn(m)
+		})
+	}
+	return true
+}
+
+// updateAccessible 更新按钮的可访问角色, 名称, 值和描述, 在主线程执行, 状态变化时调用
+//
+//	名称: 文本, 文本为空时(只有图标)使用提示
+//	值: 当前状态的文本, 见 AccessibleStateNames, LCL 的可访问对象没有状态标志
+//	描述: 提示
+//	动作没有对应的 LCL 属性, 通过 AccessibleActions 和 DoAccessibleAction 提供
+func (m *TButton) updateAccessible() {
+	if !m.IsValid() {
+		return
+	}
+	hint := m.Hint()
+	m.lock.RLock()
+	role := m.accessibleRole
+	name := strings.ReplaceAll(m.text, "\n", " ")
+	m.lock.RUnlock()
+	if name == "" {
+		name = hint
+	}
+	m.SetAccessibleRole(lclAccessibleRole(role))
+	m.SetAccessibleName(name)
+	m.SetAccessibleValue(accessibleStateText(m.AccessibleStates()))
+	m.SetAccessibleDescription(hint)
+}
+
+// accessibleStateText 返回状态集合的文本, 用逗号分隔
+func accessibleStateText(states TAccessibleStates) string {
+	var names []string
+	for state, name := range AccessibleStateNames {
+		if states.In(TAccessibleState(state)) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// lclAccessibleRole 返回按钮角色对应的 LCL 角色
+//
+//	页签同组只有一个已选择, 与单选按钮相同
+func lclAccessibleRole(role TAccessibleRole) types.TLazAccessibilityRole {
+	switch role {
+	case ArToggleButton:
+		return types.LarCheckBox
+	case ArRadioButton, ArTab:
+		return types.LarRadioButton
+	}
+	return types.LarButton
+}
+
+// SetHint 设置提示, 同时作为可访问描述
+func (m *TButton) SetHint(value string) {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.ICustomGraphicControl.SetHint(value)
+		m.updateAccessible()
+	})
+	m.updateAction(func(action lcl.ICustomAction) {
+		action.SetHint(value)
+	})
+}
+
+// setAccessibleRole 设置按钮的可访问角色, 默认 ArButton
+func (m *TButton) setAccessibleRole(role TAccessibleRole) {
+	m.lock.Lock()
+	m.accessibleRole = role
+	m.lock.Unlock()
+	runOnMainThread(m.updateAccessible)
+}
+
+// setSelected 设置按钮作为页签时是否已选择, 只影响可访问状态
+func (m *TButton) setSelected(selected bool) {
+	m.lock.Lock()
+	changed := m.selected != selected
+	m.selected = selected
+	m.lock.Unlock()
+	if changed {
+		runOnMainThread(m.updateAccessible)
+	}
+}
+
+// updateAccessible 更新页内容区的可访问名称, 与页签文本相同
+func (m *TPage) updateAccessible() {
+	if !m.IsValid() {
+		return
+	}
+	m.SetAccessibleName(m.button.Caption())
+}
+
+// updateAccessible 更新输入框的可访问值
+func (m *TInput) updateAccessible() {
+	if !m.IsValid() {
+		return
+	}
+	m.lock.RLock()
+	text := m.Text
+	m.lock.RUnlock()
+	m.SetAccessibleValue(text)
+}
@@ -0,0 +1,24 @@
+package wg
+
+import (
+	"github.com/energye/lcl/types"
+	"testing"
+)
+
+func TestAccessibleStateText(t *testing.T) {
+	tests := []struct {
+		states TAccessibleStates
+		want   string
+	}{
+		{types.NewSet(), ""},
+		{types.NewSet(AsDisabled), "disabled"},
+		{types.NewSet(AsSelected), "selected"},
+		{types.NewSet(AsChecked, AsPressed), "pressed, checked"},
+		{types.NewSet(AsSelected, AsDisabled, AsChecked), "disabled, checked, selected"},
+	}
+	for _, tt := range tests {
+		if got := accessibleStateText(tt.states); got != tt.want {
+			t.Errorf("accessibleStateText(%v) = %q, want %q", tt.states, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/widget/assets"
+	"strings"
+)
+
+// 资源图片缓存, 同一资源只解码一次, 控件用 Assign 复制共享的图片, 只在主线程访问
+var (
+	pictureCache           = make(map[string]lcl.IPicture)
+	pictureCacheGeneration uint64
+)
+
+// sharedPicture 返回资源对应的共享图片, 在主线程执行
+//
+//	注册表变化(覆盖资源, 切换主题)后释放旧图片, 已复制到控件的图片不受影响
+func sharedPicture(asset *assets.Asset) lcl.IPicture {
+	if generation := assets.Generation(); generation != pictureCacheGeneration {
+		for key, picture := range pictureCache {
+			picture.Free()
+			delete(pictureCache, key)
+		}
+		pictureCacheGeneration = generation
+	}
+	picture, ok := pictureCache[asset.Key]
+	if !ok {
+		picture = lcl.NewPicture()
+		loadPictureFromBytes(picture, asset.Data)
+		pictureCache[asset.Key] = picture
+	}
+	return picture
+}
+
+// assetScale 按屏幕 DPI 选择资源倍数, 在主线程执行
+func assetScale() int {
+	scale := int((lcl.Screen.PixelsPerInch() + 48) / 96)
+	if scale < 1 {
+		return 1
+	}
+	return scale
+}
+
+// loadAsset 检查资源是否存在, 之后在主线程按 DPI 选择变体加载到图片
+//
+//	资源不存在时返回错误, 图片不变; 检查在调用的 goroutine 中完成, 所以可以同步返回错误
+func loadAsset(control lcl.IComponent, picture func() lcl.IPicture, name string) error {
+	if _, err := assets.Lookup(name, assets.Options{}); err != nil {
+		return err
+	}
+	runOnMainThread(func() {
+		if !control.IsValid() {
+			return
+		}
+		target := picture()
+		if target == nil {
+			return
+		}
+		asset, err := assets.Lookup(name, assets.Options{Scale: assetScale()})
+		if err != nil {
+			return
+		}
+		target.Assign(sharedPicture(asset))
+	})
+	return nil
+}
+
+// SetIconAsset 从资源注册表设置中间图标, 例如 "tab/scroll-left"
+func (m *TButton) SetIconAsset(name string) error {
+	return loadAsset(m, func() lcl.IPicture { return m.icon }, name)
+}
+
+// SetIconFavoriteAsset 从资源注册表设置前置图标
+func (m *TButton) SetIconFavoriteAsset(name string) error {
+	return loadAsset(m, func() lcl.IPicture { return m.iconFavorite }, name)
+}
+
+// SetIconCloseAsset 从资源注册表设置关闭图标, 存在 <名称>_enter 时作为移入高亮图标
+func (m *TButton) SetIconCloseAsset(name string) error {
+	z := m.closeZone()
+	if z == nil {
+		return nil
+	}
+	if err := z.SetIconAsset(name); err != nil {
+		return err
+	}
+	if enter := strings.TrimSuffix(name, ".png") + "_enter"; assets.Has(enter, assets.Options{}) {
+		return z.SetHighlightAsset(enter)
+	}
+	z.SetHighlightFormBytes(nil)
+	return nil
+}
+
+// SetIconAsset 从资源注册表设置图标
+func (z *TIconZone) SetIconAsset(name string) error {
+	return z.loadAsset(func() lcl.IPicture { return z.icon }, name)
+}
+
+// SetHighlightAsset 从资源注册表设置移入高亮图标
+func (z *TIconZone) SetHighlightAsset(name string) error {
+	return z.loadAsset(func() lcl.IPicture { return z.highlight }, name)
+}
+
+// loadAsset 加载资源到区域图片, 加载后重新计算按钮宽度
+func (z *TIconZone) loadAsset(picture func() lcl.IPicture, name string) error {
+	err := loadAsset(z.button, func() lcl.IPicture {
+		if !z.button.hasZone(z) {
+			return nil
+		}
+		return picture()
+	}, name)
+	if err == nil {
+		z.button.AutoSizeWidth()
+	}
+	return err
+}
@@ -17,8 +17,11 @@ import (
 	"github.com/energye/lcl/lcl"
 	"github.com/energye/lcl/types"
 	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/render"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -65,8 +68,13 @@ var (
 // TButton 多功能自绘按钮
 // 颜色状态: 默认颜色, 移入颜色, 按下颜色, 禁用颜色
 // 当大小改变, 颜色改变 会重新绘制
+//
+// 方法可以在任意 goroutine 调用, 涉及 LCL 的操作会转到主线程执行.
+// 导出字段(RoundedCorner, TextAlign 等)只能在主线程直接修改,
+// 其它 goroutine 使用 SetRoundedCorners, SetTextAlign, SetTextOffset, SetTextLineSpacing
 type TButton struct {
 	lcl.ICustomGraphicControl
+	lock                               sync.RWMutex    // 保护下列状态
 	isDisable                          bool            // 是否禁用
 	alpha                              byte            // 透明度 0 ~ 255
 	radius                             int32           // 圆角度
@@ -77,14 +85,52 @@ type TButton struct {
 	IconCloseOffSetX, IconCloseOffSetY int32           // 关闭按钮偏移位置
 	TextAlign                          TextAlign       // 该校对齐
 	TextLineSpacing                    int32           // 行间距 px
+	padding                            int32           // 自动大小时文本左右内边距
+	textRotation                       TTextRotation   // 文字方向
+	fixedSize                          bool            // 容器固定大小时不自动调整, 例如纵向页签栏横排文字
+	variant                            TButtonVariant  // 内置样式
+	sizePreset                         TButtonSize     // 尺寸预设
+	// 选中状态, 可访问
+	checked        bool                   // 是否选中, 选中时默认状态使用按下颜色
+	selected       bool                   // 作为页签时是否已选择
+	focusRect      bool                   // 作为页签或分段时是否绘制焦点框
+	iconOnly       bool                   // 只显示前置图标, 不显示文字和动作图标, 例如固定的页签
+	groupEdge      TButtonBorderDirection // 作为页签组成员时颜色线所在的边, BbdNone 不绘制
+	groupColor     colors.TColor          // 页签组颜色
+	accessibleRole TAccessibleRole        // 可访问角色
+	// 绑定的动作
+	action           lcl.ICustomAction    // 绑定的 LCL 动作
+	actionImageIndex int32                // 动作的图标序号, 没有前置图标时绘制在前置图标位置
+	actionLink       lcl.IBasicActionLink // 接收动作变更通知, 只在主线程访问
+	// 上下文菜单
+	onContextMenu TContextMenuEvent
+	// 九宫格皮肤, 设置后代替渐变背景
+	skin      *TButtonSkin
+	skinPaint *tSkinPaint // 只在主线程访问
+	// 根据当前状态背景色自动选择文字颜色
+	autoTextColor bool
+	// 禁用状态的文字颜色, ClNone 时使用字体颜色
+	disabledTextColor colors.TColor
+	// 整体不透明度, 包括背景, 文字和图标
+	opacity byte
+	fadeSeq atomic.Uint64 // 渐变动画序号
+	layer   *tLayer       // 半透明绘制时使用的离屏图像, 只在主线程访问
 	// 图标
-	iconFavorite       lcl.IPicture // 按钮前置图标, 靠左
-	iconClose          lcl.IPicture // 按钮关闭图标, 靠右
-	iconCloseHighlight lcl.IPicture // 按钮关闭图标移入高亮, 靠右
-	isEnterClose       bool         // 鼠标是否移入关闭图标
-	icon               lcl.IPicture // 按钮图标, 中间
+	iconFavorite lcl.IPicture // 按钮前置图标, 靠左
+	icon         lcl.IPicture // 按钮图标, 中间
+	// 右侧动作图标, 从左到右排列
+	zones     []*TIconZone
+	enterZone *TIconZone // 鼠标所在的动作图标
+	// 字体图标, 对应位置没有图片时绘制
+	iconFavoriteGlyph *TIconGlyph
+	iconGlyph         *TIconGlyph
+	// 命中测试, 鼠标是否在形状内, 是否在形状内按下了左键
+	hitTestMode THitTestMode
+	inShape     bool
+	pressed     bool
+	// 容器跟踪左键操作, 例如拖动页签, 只在主线程访问
+	tracker tMouseTracker
 	// 用户事件
-	onCloseClick lcl.TNotifyEvent
 	onPaint      lcl.TNotifyEvent
 	onMouseEnter lcl.TNotifyEvent
 	onMouseLeave lcl.TNotifyEvent
@@ -96,10 +142,9 @@ type TButton struct {
 	enterColor    *TButtonColor
 	downColor     *TButtonColor
 	disabledColor *TButtonColor
-	// 提示
-	closeHintTimer *time.Timer
-	closeHint      lcl.IHintWindow
-	closeHintText  string
+	// 动作图标提示
+	hintTimer  *time.Timer
+	hintWindow lcl.IHintWindow
 }
 
 func NewButton(owner lcl.IComponent) *TButton {
@@ -110,23 +155,29 @@ func NewButton(owner lcl.IComponent) *TButton {
 	m.SetParentColor(true)
 	m.Canvas().SetAntialiasingMode(types.AmOn)
 	m.SetControlStyle(m.ControlStyle().Include(types.CsParentBackground))
+	m.hitTestMode = HtShape
 	m.alpha = 255
+	m.opacity = 255
 	m.radius = 0
+	m.padding = iconMargin
+	m.accessibleRole = ArButton
+	m.actionImageIndex = -1
+	m.disabledTextColor = colors.ClNone
 	m.ICustomGraphicControl.SetOnPaint(m.paint)
 	m.ICustomGraphicControl.SetOnMouseEnter(m.Enter) // 进入
 	m.ICustomGraphicControl.SetOnMouseLeave(m.Leave) // 移出
 	m.ICustomGraphicControl.SetOnMouseDown(m.Down)   // 按下
 	m.ICustomGraphicControl.SetOnMouseUp(m.Up)       // 抬起
 	m.ICustomGraphicControl.SetOnMouseMove(m.move)
+	m.ICustomGraphicControl.SetOnContextPopup(m.contextPopup) // 右键菜单
 	m.RoundedCorner = types.NewSet(RcLeftTop, RcRightTop, RcLeftBottom, RcRightBottom)
 	m.iconFavorite = lcl.NewPicture()
-	m.iconClose = lcl.NewPicture()
-	m.iconCloseHighlight = lcl.NewPicture()
 	m.icon = lcl.NewPicture()
 	m.iconFavorite.SetOnChange(m.iconChange)
-	m.iconClose.SetOnChange(m.iconChange)
-	m.iconCloseHighlight.SetOnChange(m.iconChange)
 	m.icon.SetOnChange(m.iconChange)
+	zone := newIconZone(m, CloseZone)
+	zone.createPictures()
+	m.zones = []*TIconZone{zone}
 	// 创建按钮颜色对象
 	m.defaultColor = NewButtonColor()
 	m.defaultColor.type_ = BsDefault
@@ -145,7 +196,8 @@ func NewButton(owner lcl.IComponent) *TButton {
 	// 边框宽度 1px
 	m.SetBorderWidth(0, 1)
 
-	m.closeHint = lcl.NewHintWindow(nil)
+	m.hintWindow = lcl.NewHintWindow(nil)
+	m.updateAccessible()
 	// TODO WndProc
 	//m.SetOnWndProc(func(theMessage *types.TLMessage) {
 	//	m.InheritedWndProc(theMessage)
@@ -161,207 +213,447 @@ func NewButton(owner lcl.IComponent) *TButton {
 		m.ICustomGraphicControl.SetOnMouseDown(nil)
 		m.ICustomGraphicControl.SetOnMouseUp(nil)
 		m.ICustomGraphicControl.SetOnMouseMove(nil)
+		m.ICustomGraphicControl.SetOnContextPopup(nil)
 		m.iconFavorite.SetOnChange(nil)
-		m.iconClose.SetOnChange(nil)
-		m.iconCloseHighlight.SetOnChange(nil)
 		m.icon.SetOnChange(nil)
 		m.SetOnDestroy(nil)
+		m.freeActionLink()
 		// 释放持有资源
 		m.iconFavorite.Free()
-		m.iconClose.Free()
-		m.iconCloseHighlight.Free()
 		m.icon.Free()
+		for _, z := range m.IconZones() {
+			z.free()
+		}
 		m.defaultColor.Free()
 		m.enterColor.Free()
 		m.downColor.Free()
 		m.disabledColor.Free()
+		if m.layer != nil {
+			m.layer.free()
+		}
+		if m.skinPaint != nil {
+			m.skinPaint.free()
+		}
 	})
 	return m
 }
 
+// SetCloseHintText 设置关闭图标的提示
 func (m *TButton) SetCloseHintText(text string) {
-	m.closeHintText = text
+	if z := m.closeZone(); z != nil {
+		z.SetHint(text)
+	}
+}
+
+// invalidate 在主线程重绘
+func (m *TButton) invalidate() {
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.Invalidate()
+		}
+	})
+}
+
+// enterClose 鼠标是否移入关闭图标
+func (m *TButton) enterClose() bool {
+	z := m.EnteredZone()
+	return z != nil && z.name == CloseZone
 }
 
-// ShowHint 显示按钮的提示信息
+// ShowHint 显示鼠标所在动作图标的提示信息
 // text: 要显示的提示文本内容
 func (m *TButton) ShowHint(text string) {
 	if text == "" {
 		return
 	}
-	if m.isEnterClose && m.closeHintTimer != nil {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.enterZone != nil && m.hintTimer != nil {
 		return
 	}
-	m.closeHintTimer = time.AfterFunc(time.Second/2, func() {
-		if !m.isEnterClose {
+	zone := m.enterZone
+	m.hintTimer = time.AfterFunc(time.Second/2, func() {
+		if m.EnteredZone() != zone {
 			return
 		}
 		lcl.RunOnMainThreadAsync(func(id uint32) {
-			if !m.isEnterClose {
+			if zone == nil || m.EnteredZone() != zone {
 				return
 			}
 			cursorPos := lcl.Mouse.CursorPos()
-			hintRect := m.closeHint.CalcHintRect(0, text, 0)
+			hintRect := m.hintWindow.CalcHintRect(0, text, 0)
 			w, h := hintRect.Width(), hintRect.Height()
 			hintRect.Left = cursorPos.X + 15
 			hintRect.Top = cursorPos.Y + 15
 			hintRect.SetWidth(w)
 			hintRect.SetHeight(h)
-			m.closeHint.ActivateHintWithRectStr(hintRect, text)
+			m.hintWindow.ActivateHintWithRectStr(hintRect, text)
 		})
 	})
 }
 
 func (m *TButton) HideHint() {
-	if m.closeHintTimer != nil {
-		m.closeHintTimer.Stop()
-		m.closeHintTimer = nil
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.hintTimer != nil {
+		m.hintTimer.Stop()
+		m.hintTimer = nil
 		lcl.RunOnMainThreadAsync(func(id uint32) {
-			m.closeHint.Hide()
+			m.hintWindow.Hide()
 		})
 	}
 }
 
 func (m *TButton) Enter(sender lcl.IObject) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
 		return
 	}
+	// 从透明的圆角进入时等到移入形状再处理
+	pos := m.ScreenToClient(lcl.Mouse.CursorPos())
+	if m.PtInShape(pos.X, pos.Y) {
+		m.enterShape(sender)
+	}
+}
+
+// enterShape 鼠标移入按钮形状, 按住左键移出后再移入时恢复按下状态
+func (m *TButton) enterShape(sender lcl.IObject) {
+	m.lock.Lock()
+	m.inShape = true
 	m.buttonState = BsEnter
+	pressed := m.pressed
+	if pressed {
+		m.buttonState = BsDown
+	}
+	variant, fn := m.variant, m.onMouseEnter
+	m.lock.Unlock()
+	if pressed {
+		m.restoreClick()
+	}
+	if variant == BvLink {
+		m.Font().SetStyle(m.Font().Style().Include(types.FsUnderline))
+	}
 	m.Invalidate()
-	if m.onMouseEnter != nil {
-		m.onMouseEnter(sender)
+	m.updateAccessible()
+	if fn != nil {
+		fn(sender)
 	}
 }
 
 func (m *TButton) Leave(sender lcl.IObject) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
 		return
 	}
-	m.isEnterClose = false
+	m.lock.RLock()
+	inShape := m.inShape
+	m.lock.RUnlock()
+	if inShape {
+		m.leaveShape(sender)
+	}
+}
+
+// leaveShape 鼠标移出按钮形状
+func (m *TButton) leaveShape(sender lcl.IObject) {
+	m.HideHint()
+	m.lock.Lock()
+	m.inShape = false
+	m.enterZone = nil
 	m.buttonState = BsDefault
+	variant, fn, pressed := m.variant, m.onMouseLeave, m.pressed
+	m.lock.Unlock()
+	// 按住左键移出形状后抬起不触发点击
+	if pressed {
+		m.cancelClick()
+	}
+	lcl.Screen.SetCursor(types.CrDefault)
+	if variant == BvLink {
+		m.Font().SetStyle(m.Font().Style().Exclude(types.FsUnderline))
+	}
 	m.Invalidate()
-	if m.onMouseLeave != nil {
-		m.onMouseLeave(sender)
+	m.updateAccessible()
+	if fn != nil {
+		fn(sender)
 	}
 }
 
 func (m *TButton) Down(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
+		return
+	}
+	// 形状外的透明部分不响应, 也不触发点击
+	if !m.PtInShape(X, Y) {
+		if button == types.MbLeft {
+			m.cancelClick()
+		}
+		return
+	}
+	// 只有左键改变按钮状态和触发关闭, 其它按键只转发事件
+	if button != types.MbLeft {
+		m.forwardMouse(true, sender, button, shift, X, Y)
 		return
 	}
 	m.HideHint()
-	if !m.isCloseArea(X, Y) {
+	if m.ZoneAt(X, Y) == nil {
+		m.lock.Lock()
 		m.buttonState = BsDown
+		m.pressed = true
+		fn := m.onMouseDown
+		m.lock.Unlock()
 		m.Invalidate()
-		if m.onMouseDown != nil {
-			m.onMouseDown(sender, button, shift, X, Y)
+		m.updateAccessible()
+		if fn != nil {
+			fn(sender, button, shift, X, Y)
+		}
+		if m.tracker != nil {
+			m.tracker.trackDown(m, X, Y)
 		}
 	}
 }
 
 func (m *TButton) Up(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
+		return
+	}
+	if button != types.MbLeft {
+		m.forwardMouse(false, sender, button, shift, X, Y)
 		return
 	}
 	m.HideHint()
-	if m.isCloseArea(X, Y) {
-		if m.onCloseClick != nil {
-			m.onCloseClick(sender)
+	inShape := m.PtInShape(X, Y)
+	m.lock.Lock()
+	m.pressed = false
+	m.lock.Unlock()
+	// 拖动结束, 点击已在开始拖动时取消
+	if m.tracker != nil && m.tracker.trackUp(m, X, Y) {
+		m.lock.Lock()
+		m.buttonState = BsDefault
+		if inShape {
+			m.buttonState = BsEnter
+		}
+		m.lock.Unlock()
+		m.Invalidate()
+		m.updateAccessible()
+		return
+	}
+	if zone := m.ZoneAt(X, Y); zone != nil {
+		m.lock.RLock()
+		fn := zone.onClick
+		m.lock.RUnlock()
+		if fn != nil {
+			fn(sender)
 		}
 	} else {
-		m.buttonState = BsEnter
+		m.lock.Lock()
+		m.buttonState = BsDefault
+		if inShape {
+			m.buttonState = BsEnter
+		}
+		fn := m.onMouseUp
+		m.lock.Unlock()
 		m.Invalidate()
-		if m.onMouseUp != nil {
-			m.onMouseUp(sender, button, shift, X, Y)
+		m.updateAccessible()
+		if fn != nil {
+			fn(sender, button, shift, X, Y)
 		}
 	}
 }
 
+// forwardMouse 只调用用户的按下(down 为 true)或抬起事件, 不改变按钮状态
+func (m *TButton) forwardMouse(down bool, sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
+	m.lock.RLock()
+	fn := m.onMouseUp
+	if down {
+		fn = m.onMouseDown
+	}
+	m.lock.RUnlock()
+	if fn != nil {
+		fn(sender, button, shift, X, Y)
+	}
+}
+
 func (m *TButton) SetDisable(disable bool) {
+	m.lock.Lock()
 	m.isDisable = disable
+	m.inShape, m.pressed = false, false
 	if m.isDisable {
 		m.buttonState = BsDisabled
 	} else {
 		m.buttonState = BsDefault
 	}
-	lcl.RunOnMainThreadAsync(func(id uint32) {
-		m.Invalidate()
+	m.lock.Unlock()
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.Invalidate()
+			m.updateAccessible()
+		}
 	})
+	m.updateAction(func(action lcl.ICustomAction) {
+		action.SetEnabled(!disable)
+	})
+}
+
+// SetChecked 设置按钮是否选中, 选中时默认状态使用按下颜色
+func (m *TButton) SetChecked(checked bool) {
+	m.lock.Lock()
+	m.checked = checked
+	m.lock.Unlock()
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.Invalidate()
+			m.updateAccessible()
+		}
+	})
+	m.updateAction(func(action lcl.ICustomAction) {
+		action.SetChecked(checked)
+	})
+}
+
+// Checked 返回按钮是否选中
+func (m *TButton) Checked() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.checked
 }
 func (m *TButton) iconChange(sender lcl.IObject) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
 		return
 	}
 	m.Invalidate()
 }
 
-func (m *TButton) isCloseArea(X int32, Y int32) bool {
-	if m.isDisable || !m.IsValid() {
-		return false
-	}
-	btnRect := m.ClientRect()
-	closeW := m.iconClose.Width()
-	closeH := m.iconClose.Height()
-	closeX := btnRect.Width() - closeW - iconMargin
-	closeY := btnRect.Height()/2 - closeH/2
-	return X >= closeX && X <= btnRect.Width()-iconMargin && Y >= closeY && Y <= btnRect.Height()/2+closeH/2
-}
-
 func (m *TButton) move(sender lcl.IObject, shift types.TShiftState, X int32, Y int32) {
-	if m.isDisable || !m.IsValid() {
+	if m.Disable() || !m.IsValid() {
 		return
 	}
 	lcl.Screen.SetCursor(types.CrDefault)
-	if m.isCloseArea(X, Y) {
-		m.ShowHint(m.closeHintText)
-		if !m.isEnterClose {
-			m.isEnterClose = true
-			m.Invalidate()
+	if m.tracker != nil {
+		m.tracker.trackMove(m, X, Y)
+	}
+	inShape := m.PtInShape(X, Y)
+	m.lock.RLock()
+	wasInShape := m.inShape
+	m.lock.RUnlock()
+	if inShape && !wasInShape {
+		m.enterShape(sender)
+	} else if !inShape {
+		if wasInShape {
+			m.leaveShape(sender)
 		}
 		return
-	} else if m.isEnterClose {
-		m.isEnterClose = false
+	}
+	zone := m.ZoneAt(X, Y)
+	if m.setEnterZone(zone) {
+		// 移到另一个图标时重新计时提示
+		m.HideHint()
 		m.Invalidate()
 	}
+	if zone != nil {
+		m.lock.RLock()
+		hintText, cursor := zone.hint, zone.cursor
+		m.lock.RUnlock()
+		lcl.Screen.SetCursor(cursor)
+		m.ShowHint(hintText)
+		return
+	}
 	m.HideHint()
 }
 
-func (m *TButton) drawRoundedGradientButton(canvas lcl.ICanvas, rect types.TRect) {
-	text := m.text
-	var color *TButtonColor
+// stateColor 返回当前状态的颜色, 调用方持有锁
+func (m *TButton) stateColor() *TButtonColor {
 	switch m.buttonState {
 	case BsDefault:
-		color = m.defaultColor
+		if m.checked {
+			return m.downColor
+		}
+		return m.defaultColor
 	case BsEnter:
-		color = m.enterColor
+		return m.enterColor
 	case BsDown:
-		color = m.downColor
+		return m.downColor
 	case BsDisabled:
-		color = m.disabledColor
+		return m.disabledColor
+	}
+	return nil
+}
+
+func (m *TButton) drawRoundedGradientButton(canvas lcl.ICanvas, rect types.TRect) {
+	// 在锁内取出绘制状态, 其它 goroutine 可能同时修改
+	// 缓存的位图只在主线程访问, 在锁外获取
+	m.lock.RLock()
+	skinImage, skin := m.skinImage()
+	color := m.stateColor()
+	var style render.Style
+	if skin == nil && color != nil {
+		style = color.Style(m.RoundedCorner, m.alpha, m.radius)
 	}
-	if color == nil {
+	m.lock.RUnlock()
+	var bitMap lcl.IBitmap
+	if skin != nil {
+		bitMap = m.skinBitmap(skinImage, skin, rect.Width(), rect.Height())
+	} else if color != nil {
+		color.paint(style, rect, m.Font().PixelsPerInch())
+		bitMap = color.Bitmap()
+	} else {
 		return
 	}
-	color.tryPaint(m.RoundedCorner, rect, m.alpha, m.radius)
 
 	// 绘制到目标画布
-	canvas.DrawWithIntX2Graphic(rect.Left, rect.Top, color.bitMap)
+	if bitMap != nil {
+		canvas.DrawWithIntX2Graphic(rect.Left, rect.Top, bitMap)
+	}
+	m.drawContent(canvas, rect)
+}
+
+// drawContent 绘制按钮文字和图标
+func (m *TButton) drawContent(canvas lcl.ICanvas, rect types.TRect) {
+	m.lock.RLock()
+	text := m.text
+	state := m.buttonState
+	favoriteGlyph, iconGlyph := m.iconFavoriteGlyph, m.iconGlyph
+	autoTextColor, iconOnly := m.autoTextColor, m.iconOnly
+	disabledTextColor := m.disabledTextColor
+	layout := m.textLayoutLocked()
+	var start, end colors.TColor
+	transparent := false
+	if color := m.stateColor(); color != nil {
+		start, end, transparent = color.start, color.end, color.Transparent()
+	}
+	m.lock.RUnlock()
+
+	if state == BsDisabled && disabledTextColor != colors.ClNone {
+		canvas.FontToFont().SetColor(disabledTextColor)
+	} else if autoTextColor {
+		p := Palette()
+		// 透明背景时按调色板背景色选择
+		if transparent {
+			start, end = p.Background, p.Background
+		}
+		canvas.FontToFont().SetColor(readableTextColorOn([]colors.TColor{start, end}, []colors.TColor{p.Text, p.TextOnFill}))
+	}
 
 	// 绘制按钮文字（在原始画布上绘制，确保文字不透明）
 	brush := canvas.BrushToBrush()
 	brush.SetStyle(types.BsClear)
 
+	if iconOnly {
+		m.drawIconOnlyContent(canvas, rect, text, favoriteGlyph, state)
+		return
+	}
+	if rotation := m.TextRotation(); rotation != TrNone {
+		m.drawRotatedContent(canvas, rect, text, favoriteGlyph, iconGlyph, state, rotation)
+		return
+	}
+
 	textMargin := int32(0) // 文本与图标的间距
 	// 计算左图标占用的空间
 	leftArea := int32(0)
-	if m.iconFavorite.Width() > 0 {
-		leftArea = iconMargin + m.iconFavorite.Width() + iconMargin // 左边距10 + 图标宽度 + 图标与文本间距10
+	favW, favH := m.favoriteSize()
+	if favW > 0 {
+		leftArea = iconMargin + favW + iconMargin // 左边距10 + 图标宽度 + 图标与文本间距10
 		textMargin += iconMargin
 	}
 	// 计算右图标占用的空间
-	rightArea := int32(0)
-	if m.iconClose.Width() > 0 {
-		rightArea = iconMargin + m.iconClose.Width() + iconMargin // 右边距10 + 图标宽度 + 图标与文本间距10
+	rightArea := m.zonesWidth(canvas) // 右边距10 + 每个图标宽度和图标后间距10
+	if rightArea > 0 {
 		textMargin += -iconMargin
 	}
 
@@ -394,15 +686,15 @@ func (m *TButton) drawRoundedGradientButton(canvas lcl.ICanvas, rect types.TRect
 	// 计算多行文本的整体位置（保持垂直居中）
 	totalTextHeight := int32(len(lines)) * lineHeight // 总文本高度（无行间距）
 	// 添加行间距
-	totalTextHeight = totalTextHeight + (int32(len(lines))-1)*m.TextLineSpacing
+	totalTextHeight = totalTextHeight + (int32(len(lines))-1)*layout.lineSpacing
 
 	// 文本区域起始Y坐标（垂直居中）
-	startY := rect.Top + m.TextOffSetY + (rect.Height()-totalTextHeight)/2
-	textBaseX := rect.Left + m.TextOffSetX + textMargin
+	startY := rect.Top + layout.offsetY + (rect.Height()-totalTextHeight)/2
+	textBaseX := rect.Left + layout.offsetX + textMargin
 	for i, line := range lines {
 		lineSize := canvas.TextExtentWithStr(line)
 		var textX int32
-		switch m.TextAlign {
+		switch layout.align {
 		case TextAlignRight:
 			textX = textBaseX + leftArea + (availWidth - lineSize.Cx)
 		case TextAlignLeft:
@@ -410,31 +702,60 @@ func (m *TButton) drawRoundedGradientButton(canvas lcl.ICanvas, rect types.TRect
 		default:
 			textX = textBaseX + (rect.Width()-lineSize.Cx)/2
 		}
-		textY := startY + int32(i)*(lineHeight+m.TextLineSpacing) + (lineHeight-lineSize.Cy)/2
+		textY := startY + int32(i)*(lineHeight+layout.lineSpacing) + (lineHeight-lineSize.Cy)/2
 		canvas.TextOutWithIntX2Str(textX, textY, line)
 	}
 
-	// 左: 绘制图标 favorite
-	favY := rect.Height()/2 - m.iconFavorite.Height()/2
-	canvas.DrawWithIntX2Graphic(iconMargin, favY, m.iconFavorite.Graphic())
-
-	// 右: 绘制图标 close
-	iconClose := m.iconClose
-	if m.isEnterClose {
-		iconClose = m.iconCloseHighlight
+	// 左: 绘制图标 favorite, 没有时绘制绑定动作的图标
+	favY := rect.Height()/2 - favH/2
+	textColor := canvas.FontToFont().Color()
+	if m.iconFavorite.Width() > 0 {
+		canvas.DrawWithIntX2Graphic(iconMargin, favY, m.iconFavorite.Graphic())
+	} else if favoriteGlyph != nil {
+		drawGlyph(canvas, favoriteGlyph, iconMargin, favY, m.glyphColor(favoriteGlyph, textColor, state, colors.ClNone))
+	} else if images, index := m.actionImages(); images != nil {
+		images.Draw(canvas, iconMargin, favY, index, !m.Disable())
 	}
-	closeX := rect.Width() - iconClose.Width() - iconMargin
-	closeY := rect.Height()/2 - iconClose.Height()/2
-	canvas.DrawWithIntX2Graphic(closeX, closeY, iconClose.Graphic())
+
+	// 右: 绘制动作图标
+	m.drawZones(canvas, rect, textColor)
 
 	// 中间: 绘制图标 icon
-	iconW, iconH := m.icon.Width(), m.icon.Height()
-	iconX := rect.Left + (rect.Width()-iconW)/2
-	iconY := rect.Top + (rect.Height()-iconH)/2
-	canvas.DrawWithIntX2Graphic(iconX, iconY, m.icon.Graphic())
+	m.drawCenterIcon(canvas, rect, iconGlyph, textColor, state)
+}
+
+// drawCenterIcon 绘制中间图标, 没有图片时绘制字体图标
+func (m *TButton) drawCenterIcon(canvas lcl.ICanvas, rect types.TRect, iconGlyph *TIconGlyph, textColor colors.TColor, state TButtonState) {
+	if m.icon.Width() > 0 || iconGlyph == nil {
+		iconW, iconH := m.icon.Width(), m.icon.Height()
+		iconX := rect.Left + (rect.Width()-iconW)/2
+		iconY := rect.Top + (rect.Height()-iconH)/2
+		canvas.DrawWithIntX2Graphic(iconX, iconY, m.icon.Graphic())
+	} else {
+		iconW, iconH := measureGlyph(canvas, iconGlyph)
+		iconX := rect.Left + (rect.Width()-iconW)/2
+		iconY := rect.Top + (rect.Height()-iconH)/2
+		drawGlyph(canvas, iconGlyph, iconX, iconY, m.glyphColor(iconGlyph, textColor, state, colors.ClNone))
+	}
+}
+
+// favoriteSize 返回前置图标大小, 依次使用图片, 字体图标, 绑定动作的图标
+func (m *TButton) favoriteSize() (width, height int32) {
+	if m.iconFavorite.Width() > 0 {
+		return m.iconFavorite.Width(), m.iconFavorite.Height()
+	}
+	if glyph, _ := m.glyphs(); glyph != nil {
+		return measureGlyph(m.Canvas(), glyph)
+	}
+	if images, _ := m.actionImages(); images != nil {
+		return images.Width(), images.Height()
+	}
+	return 0, 0
 }
 
 func (m *TButton) Disable() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	return m.isDisable
 }
 
@@ -443,142 +764,175 @@ func (m *TButton) SetCaption(value string) {
 }
 
 func (m *TButton) Caption() string {
-	return m.text
+	return m.Text()
 }
 
 func (m *TButton) SetText(value string) {
+	m.lock.Lock()
 	m.text = value
+	m.lock.Unlock()
 	m.AutoSizeWidth()
+	runOnMainThread(m.updateAccessible)
+	m.updateAction(func(action lcl.ICustomAction) {
+		action.SetCaption(escapeAccelChars(value))
+	})
 }
 
 // 自动大小, 根据文本宽自动调整按钮宽度
 func (m *TButton) AutoSizeWidth() {
-	if m.autoSize {
+	m.lock.RLock()
+	autoSize := m.autoSize && !m.fixedSize
+	m.lock.RUnlock()
+	if autoSize {
 		lcl.RunOnMainThreadAsync(func(id uint32) {
-			if m.Canvas() != nil {
+			if m.IsValid() && m.Canvas() != nil {
+				rotated := m.TextRotation() != TrNone
 				leftArea := int32(0)
-				if m.iconFavorite.Width() > 0 {
-					leftArea = iconMargin + m.iconFavorite.Width() + iconMargin
-				}
-				rightArea := int32(0)
-				if m.iconClose.Width() > 0 {
-					rightArea = iconMargin + m.iconClose.Width() + iconMargin
+				if favW, favH := m.favoriteSize(); favW > 0 {
+					if rotated {
+						favW = favH
+					}
+					leftArea = iconMargin + favW + iconMargin
 				}
-				textWidth := m.Canvas().TextWidthWithStr(m.text)
-				width := textWidth + leftArea + rightArea + iconMargin*2
-				if m.Width() != width {
+				rightArea := m.zonesWidth(m.Canvas())
+				m.lock.RLock()
+				text, padding := m.text, m.padding
+				m.lock.RUnlock()
+				textWidth := m.Canvas().TextWidthWithStr(text)
+				width := textWidth + leftArea + rightArea + padding*2
+				// 文字旋转时调整高度
+				if rotated {
+					if m.Height() != width {
+						m.SetHeight(width)
+					}
+				} else if m.Width() != width {
 					m.SetWidth(width)
 				}
 			}
 		})
 	} else {
-		m.Invalidate()
+		m.invalidate()
 	}
 }
 
 func (m *TButton) Text() string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	return m.text
 }
 
+// SetAutoTextColor 设置是否根据背景色自动选择文字颜色
+//
+//	在调色板的 Text 和 TextOnFill 中选择与当前状态渐变两端对比度较高的颜色, 关闭后恢复字体颜色
+func (m *TButton) SetAutoTextColor(auto bool) {
+	m.lock.Lock()
+	m.autoTextColor = auto
+	m.lock.Unlock()
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		if !auto {
+			m.Canvas().FontToFont().SetColor(m.Font().Color())
+		}
+		m.Invalidate()
+	})
+}
+
+// AutoTextColor 返回是否根据背景色自动选择文字颜色
+func (m *TButton) AutoTextColor() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.autoTextColor
+}
+
 // SetAutoSize 设置按钮的自动大小属性
 //
 //	当启用自动大小时，按钮会根据其内容自动调整大小
 //	Note: 当前需要在第一次设置文本之前设置生效
 func (m *TButton) SetAutoSize(v bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.autoSize = v
 }
 
 func (m *TButton) SetIconFavorite(filePath string) {
-	if !m.IsValid() {
-		return
-	}
-	m.iconFavorite.LoadFromFile(filePath)
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.iconFavorite.LoadFromFile(filePath)
+	})
 }
 
 func (m *TButton) SetIconFavoriteFormBytes(pngData []byte) {
-	if !m.IsValid() {
-		return
-	}
-	if pngData == nil {
-		m.iconFavorite.Clear()
-		return
-	}
-	mem := lcl.NewMemoryStream()
-	defer mem.Free()
-	lcl.StreamHelper.WriteBuffer(mem, pngData)
-	mem.SetPosition(0)
-	m.iconFavorite.LoadFromStream(mem)
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		loadPictureFromBytes(m.iconFavorite, pngData)
+	})
 }
 
 func (m *TButton) SetIcon(filePath string) {
-	if !m.IsValid() {
-		return
-	}
-	m.icon.LoadFromFile(filePath)
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.icon.LoadFromFile(filePath)
+	})
 }
 
 func (m *TButton) SetIconFormBytes(pngData []byte) {
-	if !m.IsValid() {
-		return
-	}
-	if pngData == nil {
-		m.icon.Clear()
-		return
-	}
-	mem := lcl.NewMemoryStream()
-	defer mem.Free()
-	lcl.StreamHelper.WriteBuffer(mem, pngData)
-	mem.SetPosition(0)
-	m.icon.LoadFromStream(mem)
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		loadPictureFromBytes(m.icon, pngData)
+	})
 }
 
+// SetIconClose 设置关闭图标, 同时加载同目录下的 <名称>_enter.png 作为移入高亮图标
 func (m *TButton) SetIconClose(filePath string) {
-	if !m.IsValid() {
+	z := m.closeZone()
+	if z == nil {
 		return
 	}
 	path, name := filepath.Split(filePath)
 	ns := strings.Split(name, ".")
-	enterFilePath := filepath.Join(path, ns[0]+"_enter.png")
-	m.iconClose.LoadFromFile(filePath)
-	m.SetIconCloseHighlight(enterFilePath)
+	z.SetIcon(filePath)
+	z.SetHighlight(filepath.Join(path, ns[0]+"_enter.png"))
 }
 
 func (m *TButton) SetIconCloseHighlight(filePath string) {
-	if !m.IsValid() {
-		return
+	if z := m.closeZone(); z != nil {
+		z.SetHighlight(filePath)
 	}
-	m.iconCloseHighlight.LoadFromFile(filePath)
 }
 
 func (m *TButton) SetIconCloseFormBytes(pngData []byte) {
-	if !m.IsValid() {
-		return
+	if z := m.closeZone(); z != nil {
+		z.SetIconFormBytes(pngData)
 	}
-	if pngData == nil {
-		m.iconClose.Clear()
-		return
-	}
-	mem := lcl.NewMemoryStream()
-	defer mem.Free()
-	lcl.StreamHelper.WriteBuffer(mem, pngData)
-	mem.SetPosition(0)
-	m.iconClose.LoadFromStream(mem)
-
 }
 
 func (m *TButton) SetIconCloseHighlightFormBytes(pngData []byte) {
-	if !m.IsValid() {
-		return
+	if z := m.closeZone(); z != nil {
+		z.SetHighlightFormBytes(pngData)
 	}
+}
+
+// loadPictureFromBytes 从 PNG 数据加载图片, 数据为 nil 时清空图片
+func loadPictureFromBytes(picture lcl.IPicture, pngData []byte) {
 	if pngData == nil {
-		m.iconCloseHighlight.Clear()
+		picture.Clear()
 		return
 	}
 	mem := lcl.NewMemoryStream()
 	defer mem.Free()
 	lcl.StreamHelper.WriteBuffer(mem, pngData)
 	mem.SetPosition(0)
-	m.iconCloseHighlight.LoadFromStream(mem)
+	picture.LoadFromStream(mem)
 }
 
 // 绘制事件
@@ -590,32 +944,75 @@ func (m *TButton) paint(sender lcl.IObject) {
 	if canvas == nil || !canvas.IsValid() {
 		return
 	}
-	m.drawRoundedGradientButton(canvas, m.ClientRect())
-	if m.onPaint != nil {
-		m.onPaint(sender)
+	if opacity := m.Opacity(); opacity == 255 {
+		m.drawRoundedGradientButton(canvas, m.ClientRect())
+	} else if opacity > 0 {
+		m.drawTranslucent(canvas, m.ClientRect(), opacity)
+	}
+	m.lock.RLock()
+	fn, focusRect := m.onPaint, m.focusRect
+	groupEdge, groupColor := m.groupEdge, m.groupColor
+	m.lock.RUnlock()
+	if groupEdge != BbdNone {
+		m.drawGroupMark(canvas, m.ClientRect(), groupEdge, groupColor)
+	}
+	if focusRect {
+		rect := m.ClientRect()
+		rect.Left += 3
+		rect.Top += 3
+		rect.Right -= 3
+		rect.Bottom -= 3
+		canvas.DrawFocusRect(rect)
+	}
+	if fn != nil {
+		fn(sender)
+	}
+}
+
+// setFocusRect 设置作为页签或分段时是否绘制焦点框, 焦点由所属的 TTab 或 TSegmented 管理
+func (m *TButton) setFocusRect(focusRect bool) {
+	m.lock.Lock()
+	changed := m.focusRect != focusRect
+	m.focusRect = focusRect
+	m.lock.Unlock()
+	if changed {
+		m.invalidate()
 	}
 }
+
 func (m *TButton) SetOnCloseClick(fn lcl.TNotifyEvent) {
-	m.onCloseClick = fn
+	if z := m.closeZone(); z != nil {
+		z.SetOnClick(fn)
+	}
 }
 
 func (m *TButton) SetOnPaint(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onPaint = fn
 }
 
 func (m *TButton) SetOnMouseDown(fn lcl.TMouseEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onMouseDown = fn
 }
 
 func (m *TButton) SetOnMouseUp(fn lcl.TMouseEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onMouseUp = fn
 }
 
 func (m *TButton) SetOnMouseEnter(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onMouseEnter = fn
 }
 
 func (m *TButton) SetOnMouseLeave(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onMouseLeave = fn
 }
 
@@ -623,13 +1020,16 @@ func (m *TButton) SetOnMouseLeave(fn lcl.TNotifyEvent) {
 // start: 按钮默认状态下的起始颜色
 // end: 按钮默认状态下的结束颜色
 func (m *TButton) SetDefaultColor(start, end colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	// 更新按钮默认颜色配置
 	m.defaultColor.start = start
 	m.defaultColor.end = end
-	m.defaultColor.canPaint = true
 }
 
 func (m *TButton) DefaultColor() (start, end colors.TColor) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	start = m.defaultColor.start
 	end = m.defaultColor.end
 	return
@@ -639,12 +1039,15 @@ func (m *TButton) DefaultColor() (start, end colors.TColor) {
 // start: 渐变开始颜色
 // end: 渐变结束颜色
 func (m *TButton) SetEnterColor(start, end colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.enterColor.start = start
 	m.enterColor.end = end
-	m.enterColor.canPaint = true
 }
 
 func (m *TButton) EnterColor() (start, end colors.TColor) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	start = m.enterColor.start
 	end = m.enterColor.end
 	return
@@ -654,12 +1057,15 @@ func (m *TButton) EnterColor() (start, end colors.TColor) {
 // start: 按下状态渐变起始颜色
 // end: 按下状态渐变结束颜色
 func (m *TButton) SetDownColor(start, end colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.downColor.start = start
 	m.downColor.end = end
-	m.downColor.canPaint = true
 }
 
 func (m *TButton) DownColor() (start, end colors.TColor) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	start = m.downColor.start
 	end = m.downColor.end
 	return
@@ -669,13 +1075,16 @@ func (m *TButton) DownColor() (start, end colors.TColor) {
 // start: 渐变起始颜色
 // end: 渐变结束颜色
 func (m *TButton) SetDisabledColor(start, end colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.disabledColor.start = start
 	m.disabledColor.end = end
-	m.disabledColor.canPaint = true
 }
 
 // DisabledColor 返回禁用颜色
 func (m *TButton) DisabledColor() (start, end colors.TColor) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	start = m.disabledColor.start
 	end = m.disabledColor.end
 	return
@@ -701,6 +1110,8 @@ func (m *TButton) SetColorGradient(start, end colors.TColor) {
 //	该函数会同时设置按钮在默认、悬停、按下和禁用状态下的边框颜色
 //	为统一的颜色值，实现按钮边框颜色的整体变更
 func (m *TButton) SetBorderColor(direction TButtonBorderDirection, color colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.defaultColor.SetBorderColor(direction, color)
 	m.enterColor.SetBorderColor(direction, DarkenColor(color, 0.1))
 	m.downColor.SetBorderColor(direction, DarkenColor(color, 0.2))
@@ -709,6 +1120,8 @@ func (m *TButton) SetBorderColor(direction TButtonBorderDirection, color colors.
 // SetBorderWidth 设置按钮的边框宽度
 // width: 边框宽度值
 func (m *TButton) SetBorderWidth(direction TButtonBorderDirection, width int32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.defaultColor.SetBorderWidth(direction, width)
 	m.enterColor.SetBorderWidth(direction, width)
 	m.downColor.SetBorderWidth(direction, width)
@@ -721,24 +1134,82 @@ func (m *TButton) SetBorderWidth(direction TButtonBorderDirection, width int32)
 // 该函数会同时设置按钮的默认、悬停、按下和禁用四种状态的边框样式
 // 为相同的值，实现统一的边框外观效果
 func (m *TButton) SetBorderDirections(directions TButtonBorderDirections) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.defaultColor.Border.Direction = directions
 	m.enterColor.Border.Direction = directions
 	m.downColor.Border.Direction = directions
 	m.disabledColor.Border.Direction = directions
-	m.defaultColor.canPaint = true
-	m.enterColor.canPaint = true
-	m.downColor.canPaint = true
-	m.disabledColor.canPaint = true
 }
 
 func (m *TButton) SetAlpha(alpha byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.alpha = alpha
 }
 
 func (m *TButton) SetRadius(radius int32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.radius = radius
 }
 
+// SetRoundedCorners 设置圆角方向并重绘, 可以在任意 goroutine 调用
+func (m *TButton) SetRoundedCorners(corners TRoundedCorners) {
+	m.lock.Lock()
+	m.RoundedCorner = corners
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// RoundedCorners 返回圆角方向
+func (m *TButton) RoundedCorners() TRoundedCorners {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.RoundedCorner
+}
+
+// SetTextAlign 设置多行文字的对齐方式并重绘, 可以在任意 goroutine 调用
+func (m *TButton) SetTextAlign(align TextAlign) {
+	m.lock.Lock()
+	m.TextAlign = align
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// SetTextOffset 设置文字显示偏移位置并重绘, 可以在任意 goroutine 调用
+func (m *TButton) SetTextOffset(x, y int32) {
+	m.lock.Lock()
+	m.TextOffSetX, m.TextOffSetY = x, y
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// SetTextLineSpacing 设置多行文字的行间距并重绘, 可以在任意 goroutine 调用
+func (m *TButton) SetTextLineSpacing(spacing int32) {
+	m.lock.Lock()
+	m.TextLineSpacing = spacing
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// tTextLayout 绘制时取出的文字布局设置
+type tTextLayout struct {
+	align            TextAlign
+	offsetX, offsetY int32
+	lineSpacing      int32
+}
+
+// textLayoutLocked 返回文字布局设置, 调用方持有锁
+func (m *TButton) textLayoutLocked() tTextLayout {
+	return tTextLayout{
+		align:       m.TextAlign,
+		offsetX:     m.TextOffSetX,
+		offsetY:     m.TextOffSetY,
+		lineSpacing: m.TextLineSpacing,
+	}
+}
+
 func (m *TButton) Free() {
 	m.ICustomGraphicControl.Free()
 }
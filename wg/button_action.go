@@ -0,0 +1,158 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"strings"
+)
+
+// BindAction 绑定 LCL 动作, action 为 nil 时解除绑定
+//
+//	动作 -> 按钮: 文本, 提示, 图标序号(动作列表的图片列表), 启用, 选中状态
+//	按钮 -> 动作: SetText, SetHint, SetDisable, SetChecked 同步回动作
+//	点击按钮和快捷键执行动作(OnExecute), 按钮设置了 OnClick 时按 LCL 规则只调用 OnClick
+//
+//	LCL 控件的动作链接只更新控件的 Caption, Hint, Enabled, 按钮自己的文本, 启用, 选中状态和
+//	图标序号由按钮另外创建的动作链接同步, 动作的这些属性变化时 LCL 通知所有链接,
+//	不占用动作的事件, 动作可以同时用于菜单, 工具栏
+func (m *TButton) BindAction(action lcl.ICustomAction) {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.lock.Lock()
+		m.action = action
+		m.actionImageIndex = -1
+		m.lock.Unlock()
+		if action == nil {
+			m.freeActionLink()
+			m.ICustomGraphicControl.SetAction(nil)
+			m.Invalidate()
+			return
+		}
+		// 由 LCL 动作链接负责执行动作, 快捷键, 以及提示的变更通知
+		m.ICustomGraphicControl.SetAction(action)
+		if m.actionLink == nil {
+			m.actionLink = lcl.NewBasicActionLink(m)
+			m.actionLink.SetOnChange(func(sender lcl.IObject) {
+				m.syncFromAction()
+			})
+		}
+		m.actionLink.SetAction(action)
+		m.syncFromAction()
+	})
+}
+
+// BoundAction 返回绑定的动作, 未绑定时返回 nil
+func (m *TButton) BoundAction() lcl.ICustomAction {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.action
+}
+
+// freeActionLink 解除并释放同步按钮状态的动作链接, 在主线程执行
+func (m *TButton) freeActionLink() {
+	if m.actionLink == nil {
+		return
+	}
+	m.actionLink.SetOnChange(nil)
+	m.actionLink.SetAction(nil)
+	m.actionLink.Free()
+	m.actionLink = nil
+}
+
+// syncFromAction 从绑定的动作同步文本, 启用, 选中状态和图标序号, 在主线程执行
+func (m *TButton) syncFromAction() {
+	m.lock.RLock()
+	action := m.action
+	m.lock.RUnlock()
+	if action == nil || !action.IsValid() || !m.IsValid() {
+		return
+	}
+	text := stripAccelChars(action.Caption())
+	disable := !action.Enabled()
+	checked := action.Checked()
+	imageIndex := action.ImageIndex()
+
+	m.lock.Lock()
+	textChanged := m.text != text
+	changed := textChanged || m.isDisable != disable || m.checked != checked || m.actionImageIndex != imageIndex
+	m.text = text
+	m.checked = checked
+	m.actionImageIndex = imageIndex
+	if m.isDisable != disable {
+		m.isDisable = disable
+		if disable {
+			m.buttonState = BsDisabled
+		} else {
+			m.buttonState = BsDefault
+		}
+	}
+	m.lock.Unlock()
+	if !changed {
+		return
+	}
+	if textChanged {
+		m.AutoSizeWidth()
+	}
+	m.Invalidate()
+	m.updateAccessible()
+}
+
+// updateAction 把按钮的修改同步到绑定的动作, 在主线程执行
+func (m *TButton) updateAction(fn func(action lcl.ICustomAction)) {
+	m.lock.RLock()
+	action := m.action
+	m.lock.RUnlock()
+	if action == nil {
+		return
+	}
+	runOnMainThread(func() {
+		if action.IsValid() {
+			fn(action)
+		}
+	})
+}
+
+// actionImages 返回绑定动作的图片列表和图标序号, 没有时返回 nil
+func (m *TButton) actionImages() (lcl.ICustomImageList, int32) {
+	m.lock.RLock()
+	action, index := m.action, m.actionImageIndex
+	m.lock.RUnlock()
+	if action == nil || index < 0 || !action.IsValid() {
+		return nil, -1
+	}
+	list := action.ActionList()
+	if list == nil || !list.IsValid() {
+		return nil, -1
+	}
+	images := list.Images()
+	if images == nil || !images.IsValid() || index >= images.Count() {
+		return nil, -1
+	}
+	return images, index
+}
+
+// stripAccelChars 去掉 LCL 文本中的快捷键标记 &, && 表示 & 字符
+func stripAccelChars(caption string) string {
+	if !strings.Contains(caption, "&") {
+		return caption
+	}
+	var sb strings.Builder
+	runes := []rune(caption)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '&' {
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				sb.WriteRune('&')
+				i++
+			}
+			continue
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
+
+// escapeAccelChars 转义文本中的 &, 写入动作文本时不作为快捷键标记
+func escapeAccelChars(text string) string {
+	return strings.ReplaceAll(text, "&", "&&")
+}
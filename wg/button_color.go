@@ -4,6 +4,8 @@ import (
 	"github.com/energye/lcl/lcl"
 	"github.com/energye/lcl/types"
 	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/render"
+	"image/color"
 	"math"
 )
 
@@ -23,13 +25,11 @@ type TButtonBorderDirections = types.TSet
 
 // TButtonColor 按钮颜色
 type TButtonColor struct {
-	start    colors.TColor     // 按钮起始渐变颜色
-	end      colors.TColor     // 按钮结束渐变颜色
-	Border   TButtonBorder     // 按钮边框
-	img      lcl.ILazIntfImage // 缓存
-	bitMap   lcl.IBitmap       // 缓存
-	type_    int32             // 按钮类型, 自定义, 区分类型
-	canPaint bool              // 是否绘制
+	start  colors.TColor // 按钮起始渐变颜色
+	end    colors.TColor // 按钮结束渐变颜色
+	Border TButtonBorder // 按钮边框
+	entry  *paintEntry   // 共享缓存中的背景位图, 只在主线程访问
+	type_  int32         // 按钮类型, 自定义, 区分类型
 }
 
 // 按钮边框
@@ -48,21 +48,21 @@ type TButtonBorder struct {
 }
 
 func NewButtonColor() *TButtonColor {
-	m := &TButtonColor{
-		img:    lcl.NewLazIntfImageWithIntX2RIQFlags(0, 0, types.NewSet(types.RiqfRGB, types.RiqfAlpha)),
-		bitMap: lcl.NewBitmap(),
-	}
-	m.bitMap.SetPixelFormat(types.Pf32bit)
-	return m
+	return &TButtonColor{}
 }
 
+// Free 释放对共享背景位图的引用
 func (m *TButtonColor) Free() {
-	if m.img != nil && m.img.IsValid() {
-		m.img.Free()
-	}
-	if m.bitMap != nil && m.bitMap.IsValid() {
-		m.bitMap.Free()
+	paintCache.release(m.entry)
+	m.entry = nil
+}
+
+// Bitmap 返回最近一次绘制的背景位图, 位图由共享缓存持有, 不能释放
+func (m *TButtonColor) Bitmap() lcl.IBitmap {
+	if m.entry == nil {
+		return nil
 	}
+	return m.entry.bitMap
 }
 
 // SetBorderWidth 设置按钮指定方向的边框宽度
@@ -81,7 +81,6 @@ func (m *TButtonColor) SetBorderWidth(direction TButtonBorderDirection, width in
 	default:
 		m.Border.width = width
 	}
-	m.canPaint = true
 }
 
 // BorderWidth 根据指定的边框方向返回对应的边框宽度
@@ -121,7 +120,6 @@ func (m *TButtonColor) SetBorderColor(direction TButtonBorderDirection, color co
 	default:
 		m.Border.color = color
 	}
-	m.canPaint = true
 }
 
 // BorderColor 根据指定的边框方向返回对应的边框颜色
@@ -144,217 +142,93 @@ func (m *TButtonColor) BorderColor(direction TButtonBorderDirection) (color colo
 	return
 }
 
-// CanPaint 检查按钮颜色是否可以绘制
-// rect: 绘制区域的矩形尺寸
-// 返回值: 当尺寸发生变化或允许绘制时返回true，否则返回false
-func (m *TButtonColor) CanPaint(rect types.TRect) bool {
-	isPaint := false
-	if m.img.Width() != rect.Width() || m.img.Height() != rect.Height() {
-		m.img.SetSize(rect.Width(), rect.Height())
-		isPaint = true
-	}
-	if m.bitMap.Width() != rect.Width() || m.bitMap.Height() != rect.Height() {
-		m.bitMap.SetSize(rect.Width(), rect.Height())
-		isPaint = true
+// paint 从共享缓存获取样式对应的背景位图, 缓存中不存在时绘制, 在主线程执行
+//
+//	style: 调用方持有按钮锁时通过 Style 取得
+//	rect: 绘制区域矩形
+//	dpi: 屏幕 DPI
+//	圆角, 透明度等按钮属性不经过 TButtonColor 设置, 所以每次都比较缓存键, 键相同时不重新绘制
+func (m *TButtonColor) paint(style render.Style, rect types.TRect, dpi int32) {
+	key := paintKey{
+		style:  style,
+		width:  rect.Width(),
+		height: rect.Height(),
+		dpi:    dpi,
 	}
-	return isPaint || m.canPaint
-}
-
-// paint 绘制按钮颜色
-// roundedCorners: 圆角设置
-// rect: 绘制区域矩形
-// alpha: 透明度值
-// radius: 圆角半径
-func (m *TButtonColor) tryPaint(roundedCorners TRoundedCorners, rect types.TRect, alpha byte, radius int32) {
-	if !m.CanPaint(rect) {
+	if m.entry != nil && m.entry.key == key {
 		return
 	}
-	m.canPaint = false
-	m.doPaint(roundedCorners, rect, alpha, radius)
+	// 先获取新位图再释放旧位图, 避免样式未变时被淘汰
+	entry := paintCache.acquire(key)
+	paintCache.release(m.entry)
+	m.entry = entry
 }
 
-// doPaint 绘制带有圆角和透明度的垂直渐变按钮图像。
-// 参数:
+// Style 返回当前颜色和边框对应的渲染样式
 //
-//	roundedCorners: 指定哪些角落需要绘制为圆角
-//	alpha: 图像的整体透明度，取值范围 0-255
-//	radius: 圆角的半径大小
-func (m *TButtonColor) doPaint(roundedCorners TRoundedCorners, rect types.TRect, alpha byte, radius int32) {
-	w, h := rect.Width(), rect.Height()
-	// 提取起始颜色和结束颜色的 RGB 分量，用于计算渐变过程中的颜色插值
-	startR := colors.Red(m.start)
-	startG := colors.Green(m.start)
-	startB := colors.Blue(m.start)
-	// 获取结束颜色分量
-	endR := colors.Red(m.end)
-	endG := colors.Green(m.end)
-	endB := colors.Blue(m.end)
-	// 处理垂直渐变（带抗锯齿圆角）
-	// 遍历图像每一行，根据当前行位置计算颜色渐变比例，并逐像素设置颜色与透明度
-	imgHeight := m.img.Height()
-	imgWidth := m.img.Width()
-	for y := int32(0); y < imgHeight; y++ {
-		// 计算颜色渐变
-		ratio := float64(y) / float64(imgHeight-1)
-		r := round(float64(startR)*(1-ratio) + float64(endR)*ratio)
-		g := round(float64(startG)*(1-ratio) + float64(endG)*ratio)
-		b := round(float64(startB)*(1-ratio) + float64(endB)*ratio)
-		color := lcl.TFPColor{Red: uint16(r) << 8, Green: uint16(g) << 8, Blue: uint16(b) << 8}
-		borderFPColor := color
-		// 注意：Alpha会在内循环中为每个像素单独设置
-		for x := int32(0); x < imgWidth; x++ {
-			alphaFactor, corners := m.calculateRoundedAlpha(roundedCorners, x, y, imgWidth, imgHeight, radius)
-			_ = corners
-			isBorder := false
-			borderColor := colors.TColor(0)
-			if m.Border.Direction != 0 { // 启用了边框
-				if alphaFactor < 1.0 { // 圆角范围内
-					// TODO 有点小问题. 1: 圆角的边框绘制有瑕疵, 2: 圆角的边框宽度判断未增加
-					if m.Border.Direction.In(BbdLeft) && (corners == RcLeftTop || corners == RcLeftBottom) {
-						// 左边框
-						borderColor = m.BorderColor(BbdLeft)
-						isBorder = true
-					} else if m.Border.Direction.In(BbdTop) && (corners == RcRightTop || corners == RcLeftTop) {
-						// 上边框
-						borderColor = m.BorderColor(BbdTop)
-						isBorder = true
-					} else if m.Border.Direction.In(BbdRight) && (corners == RcRightTop || corners == RcRightBottom) {
-						// 右边框
-						borderColor = m.BorderColor(BbdRight)
-						isBorder = true
-					} else if m.Border.Direction.In(BbdBottom) && (corners == RcLeftBottom || corners == RcRightBottom) {
-						// 下边框
-						borderColor = m.BorderColor(BbdBottom)
-						isBorder = true
-					}
-				} else { // 圆角外
-					if m.Border.Direction.In(BbdLeft) && x < m.BorderWidth(BbdLeft) && y < h {
-						// 左边框
-						isBorder = true
-						borderColor = m.BorderColor(BbdLeft)
-					} else if m.Border.Direction.In(BbdTop) && y < m.BorderWidth(BbdTop) && x <= w {
-						// 上边框
-						isBorder = true
-						borderColor = m.BorderColor(BbdTop)
-					} else if m.Border.Direction.In(BbdRight) && x >= w-m.BorderWidth(BbdRight) && y < h {
-						// 右边框
-						isBorder = true
-						borderColor = m.BorderColor(BbdRight)
-					} else if m.Border.Direction.In(BbdBottom) && x < w && y >= h-m.BorderWidth(BbdBottom) {
-						// 下边框
-						isBorder = true
-						borderColor = m.BorderColor(BbdBottom)
-					}
-				}
-				if borderColor != 0 {
-					borderFPColor = ColorToFPColor(borderColor, ratio)
-				}
-			}
-			actualAlpha := round(float64(alpha) * float64(alphaFactor))
-			if isBorder {
-				borderFPColor.Alpha = uint16(actualAlpha) << 8
-				m.img.SetColors(x, y, borderFPColor)
-			} else {
-				color.Alpha = uint16(actualAlpha) << 8
-				m.img.SetColors(x, y, color)
-			}
+//	roundedCorners: 圆角方向
+//	alpha: 整体透明度
+//	radius: 圆角半径
+func (m *TButtonColor) Style(roundedCorners TRoundedCorners, alpha byte, radius int32) render.Style {
+	style := render.Style{
+		Start:   ColorToNRGBA(m.start),
+		End:     ColorToNRGBA(m.end),
+		Alpha:   alpha,
+		Radius:  int(radius),
+		Corners: RoundedCornersToRender(roundedCorners),
+		NoFill:  m.Transparent(),
+	}
+	edge := func(direction TButtonBorderDirection) render.Edge {
+		if !m.Border.Direction.In(direction) {
+			return render.Edge{}
 		}
+		e := render.Edge{Width: int(m.BorderWidth(direction))}
+		// 颜色为 0 时使用背景渐变色
+		if color := m.BorderColor(direction); color != 0 {
+			e.Color = ColorToNRGBA(color)
+		}
+		return e
+	}
+	style.Border = render.Border{
+		Left:   edge(BbdLeft),
+		Top:    edge(BbdTop),
+		Right:  edge(BbdRight),
+		Bottom: edge(BbdBottom),
 	}
-	// 将处理好的图像数据加载到位图对象中，供后续使用
-	m.bitMap.LoadFromIntfImage(m.img)
+	return style
+}
+
+// Transparent 返回背景是否透明, 起始和结束颜色都为 ClNone 时不填充背景, 只绘制边框
+func (m *TButtonColor) Transparent() bool {
+	return m.start == colors.ClNone && m.end == colors.ClNone
 }
 
 func (m *TButtonColor) SetColor(start, end colors.TColor) {
 	m.start = start
 	m.end = end
-	m.canPaint = true
 }
 
-// calculateRoundedAlpha 根据给定的圆角信息和像素位置，计算该点在按钮背景中的 alpha 值以及所属的圆角类型。
-// 用于实现抗锯齿效果的圆角绘制逻辑。
-//
-// 参数说明：
-//
-//	roundedCorners: 指示哪些角落需要绘制为圆角（TRoundedCorners 类型）
-//	x, y: 当前像素点相对于控件左上角的坐标
-//	width, height: 控件的宽高尺寸
-//	radius: 预期设置的圆角半径
-//
-// 返回值说明：
-//
-//	alphaValue: 当前点的透明度值，范围 [0.0, 1.0]
-//	corners: 所属的圆角类型（如 RcLeftTop、RcRightBottom 等），若不在圆角区域则为默认值
-func (m *TButtonColor) calculateRoundedAlpha(roundedCorners TRoundedCorners, x, y, width, height, radius int32) (alphaValue float32, corners RoundedCorner) {
-	// 计算实际可用最大半径（不超过尺寸限制）
-	maxRadius := min(width/2, height/2)
-	if radius > maxRadius {
-		radius = maxRadius
-	}
-	// 如果半径被限制为0，直接返回不透明
-	if radius <= 0 {
-		alphaValue = 1.0
-		return
+// RoundedCornersToRender 转换按钮圆角方向为渲染圆角方向
+func RoundedCornersToRender(roundedCorners TRoundedCorners) render.Corners {
+	var corners render.Corners
+	if roundedCorners.In(RcLeftTop) {
+		corners |= render.CornerLeftTop
 	}
-	var (
-		cornerX, cornerY int32   // 圆角顶点坐标
-		d                float32 // 距离圆心的距离
-		inCorner         bool    // 是否在圆角内
-	)
-	// 判断当前点是否位于某个圆角区域内，并计算其到对应圆心的距离
-	// 左上角区域
-	if roundedCorners.In(RcLeftTop) && x < radius && y < radius {
-		cornerX = radius
-		cornerY = radius
-		inCorner = true
-		corners = RcLeftTop
-	} else if roundedCorners.In(RcRightTop) && x >= width-radius && y < radius {
-		// 右上角区域
-		cornerX = width - radius - 1
-		cornerY = radius
-		inCorner = true
-		corners = RcRightTop
-	} else if roundedCorners.In(RcLeftBottom) && x < radius && y >= height-radius {
-		// 左下角区域
-		cornerX = radius
-		cornerY = height - radius - 1
-		inCorner = true
-		corners = RcLeftBottom
-	} else if roundedCorners.In(RcRightBottom) && x >= width-radius && y >= height-radius {
-		// 右下角区域
-		cornerX = width - radius - 1
-		cornerY = height - radius - 1
-		inCorner = true
-		corners = RcRightBottom
+	if roundedCorners.In(RcRightTop) {
+		corners |= render.CornerRightTop
 	}
-	if !inCorner {
-		alphaValue = 1.0
-		return
+	if roundedCorners.In(RcLeftBottom) {
+		corners |= render.CornerLeftBottom
 	}
-	d = sqrt(float64(sqr(x-cornerX) + sqr(y-cornerY)))
-	// 抗锯齿过渡处理：根据距离决定 alpha 渐变值
-	const transition = 1.0
-	innerRadius := float32(radius) - transition
-	// 完全在圆角内
-	if d <= innerRadius {
-		alphaValue = 1.0
-		return
+	if roundedCorners.In(RcRightBottom) {
+		corners |= render.CornerRightBottom
 	}
-	// 完全在圆角外
-	if d >= float32(radius)+transition {
-		alphaValue = 0.0
-		return
-	}
-	// 在过渡区域内（平滑渐变）
-	alphaValue = 1.0 - (d-innerRadius)/(2*transition)
-	return
+	return corners
 }
 
-// 辅助函数：整数最小值
-func min(a, b int32) int32 {
-	if a < b {
-		return a
-	}
-	return b
+// ColorToNRGBA 转换 TColor 为不透明的 color.NRGBA
+func ColorToNRGBA(c colors.TColor) color.NRGBA {
+	return color.NRGBA{R: colors.Red(c), G: colors.Green(c), B: colors.Blue(c), A: 255}
 }
 
 // DarkenColor 函数用于将给定的颜色按照指定因子进行暗化处理
@@ -438,11 +312,3 @@ func ColorToFPColor(color types.TColor, ratio float64) lcl.TFPColor {
 func round(v float64) float64 {
 	return math.Round(v)
 }
-
-func sqr(x int32) int32 {
-	return x * x
-}
-
-func sqrt(v float64) float32 {
-	return float32(math.Sqrt(v))
-}
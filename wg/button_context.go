@@ -0,0 +1,109 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+)
+
+// THitZone 按钮命中区域
+type THitZone = int32
+
+const (
+	HzNone     THitZone = iota // 无, 键盘菜单键触发或不在按钮内
+	HzFavorite                 // 前置图标
+	HzText                     // 文字, 图标以外的按钮区域
+	HzIcon                     // 中间图标
+	HzClose                    // 关闭图标
+	HzAction                   // 关闭图标以外的动作图标, 用 ZoneAt 获取具体区域
+)
+
+// TContextMenuEvent 上下文菜单事件
+//
+//	zone: 鼠标右键所在区域, 键盘菜单键触发时为 HzNone
+//	mousePos: 鼠标位置(控件坐标), 键盘菜单键触发时为 -1, -1
+//	handled: 设置为 true 时不弹出菜单
+type TContextMenuEvent func(sender lcl.IObject, zone THitZone, mousePos types.TPoint, handled *bool)
+
+// SetOnContextMenu 设置上下文菜单事件, 鼠标右键或 ShowContextMenu 时在弹出菜单前触发
+func (m *TButton) SetOnContextMenu(fn TContextMenuEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onContextMenu = fn
+}
+
+// ShowContextMenu 显示上下文菜单, 用于键盘菜单键(VK_APPS, Shift+F10)
+//
+//	按钮不能获得焦点, 由获得焦点的容器(TSegmented, TTab)在按键时调用
+//	先触发 OnContextMenu, 未处理时在按钮左下角弹出 PopupMenu
+func (m *TButton) ShowContextMenu() {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		handled := false
+		m.contextPopup(m, types.TPoint{X: -1, Y: -1}, &handled)
+		if handled {
+			return
+		}
+		menu := m.PopupMenu()
+		if menu == nil || !menu.IsValid() {
+			return
+		}
+		pos := m.ClientToScreen(types.TPoint{X: 0, Y: m.Height()})
+		menu.SetPopupComponent(m)
+		menu.Popup(pos.X, pos.Y)
+	})
+}
+
+// contextPopup LCL 上下文菜单事件, 鼠标右键时由 LCL 触发, 未处理时 LCL 弹出 PopupMenu
+func (m *TButton) contextPopup(sender lcl.IObject, mousePos types.TPoint, handled *bool) {
+	byMouse := mousePos.X != -1 || mousePos.Y != -1
+	// 形状外的透明部分不弹出菜单
+	if byMouse && !m.PtInShape(mousePos.X, mousePos.Y) {
+		*handled = true
+		return
+	}
+	m.lock.RLock()
+	fn := m.onContextMenu
+	m.lock.RUnlock()
+	if fn == nil {
+		return
+	}
+	zone := HzNone
+	if byMouse {
+		zone = m.HitZone(mousePos.X, mousePos.Y)
+	}
+	fn(sender, zone, mousePos, handled)
+}
+
+// HitZone 返回控件坐标 X, Y 所在的按钮区域
+func (m *TButton) HitZone(X, Y int32) THitZone {
+	if !m.IsValid() {
+		return HzNone
+	}
+	rect := m.ClientRect()
+	if !rect.PtInRect(types.TPoint{X: X, Y: Y}) || !m.PtInShape(X, Y) {
+		return HzNone
+	}
+	if zone := m.ZoneAt(X, Y); zone != nil {
+		if zone.name == CloseZone {
+			return HzClose
+		}
+		return HzAction
+	}
+	if fr, ok := m.favoriteRect(rect); ok && fr.PtInRect(types.TPoint{X: X, Y: Y}) {
+		return HzFavorite
+	}
+	iconW, iconH := m.icon.Width(), m.icon.Height()
+	if _, glyph := m.glyphs(); iconW == 0 && glyph != nil {
+		iconW, iconH = measureGlyph(m.Canvas(), glyph)
+	}
+	if iconW > 0 {
+		iconX := rect.Left + (rect.Width()-iconW)/2
+		iconY := rect.Top + (rect.Height()-iconH)/2
+		if X >= iconX && X < iconX+iconW && Y >= iconY && Y < iconY+iconH {
+			return HzIcon
+		}
+	}
+	return HzText
+}
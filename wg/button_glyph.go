@@ -0,0 +1,131 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types/colors"
+)
+
+// TIconGlyph 字体图标, 用图标字体中的一个字符作为按钮图标
+//
+//	可用于前置图标, 中间图标和关闭图标, 同一位置设置了图片时优先绘制图片
+//	颜色随按钮状态变化: 移入, 按下时加深, 禁用时使用调色板的禁用文字颜色,
+//	移入关闭图标时默认使用调色板的危险色, 可以用 SetIconCloseHoverColor 或
+//	TIconZone.SetHoverColor 按按钮修改, 不需要为每种状态准备图片
+type TIconGlyph struct {
+	FontName  string        // 字体名称, 字体需要已安装或由应用加载
+	CodePoint rune          // 字符编码
+	Size      int32         // 字体大小
+	Color     colors.TColor // 颜色, colors.ClDefault 跟随按钮文字颜色
+}
+
+// NewIconGlyph 创建字体图标, 颜色跟随按钮文字颜色
+func NewIconGlyph(fontName string, codePoint rune, size int32) *TIconGlyph {
+	return &TIconGlyph{FontName: fontName, CodePoint: codePoint, Size: size, Color: colors.ClDefault}
+}
+
+// SetIconFavoriteGlyph 设置前置字体图标, nil 清除
+func (m *TButton) SetIconFavoriteGlyph(glyph *TIconGlyph) {
+	m.lock.Lock()
+	m.iconFavoriteGlyph = copyGlyph(glyph)
+	m.lock.Unlock()
+	m.AutoSizeWidth()
+}
+
+// SetIconGlyph 设置中间字体图标, nil 清除
+func (m *TButton) SetIconGlyph(glyph *TIconGlyph) {
+	m.lock.Lock()
+	m.iconGlyph = copyGlyph(glyph)
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// SetIconCloseGlyph 设置关闭字体图标, nil 清除
+func (m *TButton) SetIconCloseGlyph(glyph *TIconGlyph) {
+	if z := m.closeZone(); z != nil {
+		z.SetGlyph(glyph)
+	}
+}
+
+// SetIconCloseHoverColor 设置鼠标移入关闭图标时字体图标的颜色, colors.ClDefault 使用调色板的危险色
+func (m *TButton) SetIconCloseHoverColor(color colors.TColor) {
+	if z := m.closeZone(); z != nil {
+		z.SetHoverColor(color)
+	}
+}
+
+// copyGlyph 复制字体图标, 调用方之后修改不影响按钮
+func copyGlyph(glyph *TIconGlyph) *TIconGlyph {
+	if glyph == nil {
+		return nil
+	}
+	c := *glyph
+	return &c
+}
+
+// glyphs 返回前置, 中间字体图标
+func (m *TButton) glyphs() (favorite, icon *TIconGlyph) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.iconFavoriteGlyph, m.iconGlyph
+}
+
+// glyphColor 返回字体图标在按钮状态下的颜色
+//
+//	highlight: 移入颜色, colors.ClNone 表示没有
+//	禁用时和文字颜色相同: DisabledTextColor, 为 ClNone 时使用字体颜色
+//	系统颜色(如 Cl3DFace)没有 RGB 分量, 移入和按下时不加深
+func (m *TButton) glyphColor(glyph *TIconGlyph, textColor colors.TColor, state TButtonState, highlight colors.TColor) colors.TColor {
+	color := glyph.Color
+	if color == colors.ClDefault {
+		color = textColor
+	}
+	switch {
+	case state == BsDisabled:
+		if disabled := m.DisabledTextColor(); disabled != colors.ClNone {
+			return disabled
+		}
+		return textColor
+	case highlight != colors.ClNone:
+		return highlight
+	case isSystemColor(color):
+		return color
+	case state == BsEnter:
+		return shadeColor(color, 0.2)
+	case state == BsDown:
+		return shadeColor(color, 0.35)
+	}
+	return color
+}
+
+// isSystemColor 是否为系统颜色或特殊颜色(ClDefault, ClNone)
+func isSystemColor(color colors.TColor) bool {
+	return uint32(color)&0xFF000000 != 0
+}
+
+// measureGlyph 返回字体图标大小
+func measureGlyph(canvas lcl.ICanvas, glyph *TIconGlyph) (width, height int32) {
+	if glyph == nil || canvas == nil {
+		return 0, 0
+	}
+	font := canvas.FontToFont()
+	name, size := font.Name(), font.Size()
+	font.SetName(glyph.FontName)
+	font.SetSize(glyph.Size)
+	ext := canvas.TextExtentWithStr(string(glyph.CodePoint))
+	font.SetName(name)
+	font.SetSize(size)
+	return ext.Cx, ext.Cy
+}
+
+// drawGlyph 在 x, y 绘制字体图标, 绘制后恢复画布字体
+func drawGlyph(canvas lcl.ICanvas, glyph *TIconGlyph, x, y int32, color colors.TColor) {
+	font := canvas.FontToFont()
+	name, size, textColor := font.Name(), font.Size(), font.Color()
+	font.SetName(glyph.FontName)
+	font.SetSize(glyph.Size)
+	font.SetColor(color)
+	canvas.TextOutWithIntX2Str(x, y, string(glyph.CodePoint))
+	font.SetName(name)
+	font.SetSize(size)
+	font.SetColor(textColor)
+}
@@ -0,0 +1,69 @@
+package wg
+
+import (
+	"github.com/energye/lcl/types"
+	"github.com/energye/widget/render"
+)
+
+// THitTestMode 按钮命中测试方式, 决定鼠标移入, 移入颜色和点击的范围
+type THitTestMode = int32
+
+const (
+	HtBounds THitTestMode = iota // 整个矩形
+	HtShape                      // 圆角形状, 圆角外的透明部分不响应鼠标, 默认
+	HtAlpha                      // 圆角形状, 有皮肤时使用当前状态皮肤图片的透明度
+)
+
+const (
+	hitCoverage = 0.5 // 圆角边缘覆盖率不小于该值时命中
+	hitAlpha    = 128 // 皮肤像素透明度不小于该值时命中
+)
+
+// SetHitTestMode 设置命中测试方式
+func (m *TButton) SetHitTestMode(mode THitTestMode) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hitTestMode = mode
+}
+
+// HitTestMode 返回命中测试方式
+func (m *TButton) HitTestMode() THitTestMode {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.hitTestMode
+}
+
+// PtInShape 控件坐标 X, Y 是否在按钮的可见形状内
+func (m *TButton) PtInShape(X, Y int32) bool {
+	if !m.IsValid() {
+		return false
+	}
+	rect := m.ClientRect()
+	w, h := int(rect.Width()), int(rect.Height())
+	x, y := int(X-rect.Left), int(Y-rect.Top)
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return false
+	}
+	m.lock.RLock()
+	mode := m.hitTestMode
+	skinImage, skin := m.skinImage()
+	style := render.Style{Radius: int(m.radius), Corners: RoundedCornersToRender(m.RoundedCorner)}
+	m.lock.RUnlock()
+	switch {
+	case mode == HtBounds:
+		return true
+	case mode == HtAlpha && skin != nil:
+		return render.NineSliceAt(skinImage, skin.Insets, skin.Mode, w, h, x, y).A >= hitAlpha
+	}
+	return style.Coverage(x, y, w, h) >= hitCoverage
+}
+
+// cancelClick 取消本次左键的 LCL 点击, 在形状外按下, 按住左键移出形状或开始拖动时调用
+func (m *TButton) cancelClick() {
+	m.SetControlState(m.ControlState().Exclude(types.CsClicked))
+}
+
+// restoreClick 按住左键移回形状时恢复 LCL 点击
+func (m *TButton) restoreClick() {
+	m.SetControlState(m.ControlState().Include(types.CsClicked))
+}
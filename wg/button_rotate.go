@@ -0,0 +1,100 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"strings"
+)
+
+// TTextRotation 按钮文字方向, 用于纵向页签
+type TTextRotation int32
+
+const (
+	TrNone TTextRotation = iota // 横排, 默认
+	TrUp                        // 逆时针旋转 90 度, 从下向上阅读
+	TrDown                      // 顺时针旋转 90 度, 从上向下阅读
+)
+
+// SetTextRotation 设置文字方向
+//
+//	旋转后内容沿纵向排列: 前置图标在阅读起点, 动作图标在阅读终点, 文字单行显示;
+//	自动大小时调整按钮高度
+func (m *TButton) SetTextRotation(rotation TTextRotation) {
+	m.lock.Lock()
+	m.textRotation = rotation
+	m.lock.Unlock()
+	m.AutoSizeWidth()
+	m.invalidate()
+}
+
+// TextRotation 返回文字方向
+func (m *TButton) TextRotation() TTextRotation {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.textRotation
+}
+
+// favoriteRect 返回前置图标位置, 只显示前置图标时居中, 没有前置图标时返回 false
+func (m *TButton) favoriteRect(rect types.TRect) (types.TRect, bool) {
+	favW, favH := m.favoriteSize()
+	if favW <= 0 {
+		return types.TRect{}, false
+	}
+	var left, top int32
+	switch rotation := m.TextRotation(); {
+	case m.isIconOnly():
+		left, top = rect.Left+(rect.Width()-favW)/2, rect.Top+(rect.Height()-favH)/2
+	case rotation == TrUp:
+		left, top = rect.Left+(rect.Width()-favW)/2, rect.Bottom-iconMargin-favH
+	case rotation == TrDown:
+		left, top = rect.Left+(rect.Width()-favW)/2, rect.Top+iconMargin
+	default:
+		left, top = rect.Left+iconMargin, rect.Top+rect.Height()/2-favH/2
+	}
+	return types.TRect{Left: left, Top: top, Right: left + favW, Bottom: top + favH}, true
+}
+
+// drawRotatedContent 绘制旋转的文字和图标
+func (m *TButton) drawRotatedContent(canvas lcl.ICanvas, rect types.TRect, text string, favoriteGlyph, iconGlyph *TIconGlyph, state TButtonState, rotation TTextRotation) {
+	textColor := canvas.FontToFont().Color()
+	startArea := int32(0)
+	if fr, ok := m.favoriteRect(rect); ok {
+		startArea = iconMargin + fr.Height() + iconMargin
+		if m.iconFavorite.Width() > 0 {
+			canvas.DrawWithIntX2Graphic(fr.Left, fr.Top, m.iconFavorite.Graphic())
+		} else if favoriteGlyph != nil {
+			drawGlyph(canvas, favoriteGlyph, fr.Left, fr.Top, m.glyphColor(favoriteGlyph, textColor, state, colors.ClNone))
+		} else if images, index := m.actionImages(); images != nil {
+			images.Draw(canvas, fr.Left, fr.Top, index, !m.Disable())
+		}
+	}
+	endArea := m.zonesWidth(canvas)
+	m.drawZones(canvas, rect, textColor)
+	m.drawCenterIcon(canvas, rect, iconGlyph, textColor, state)
+
+	line := strings.ReplaceAll(text, "\n", " ")
+	if line == "" {
+		return
+	}
+	line = truncateText(canvas, line, rect.Height()-startArea-endArea)
+	// 未旋转时的文字大小, 旋转后宽高互换
+	size := canvas.TextExtentWithStr(line)
+	font := canvas.FontToFont()
+	m.lock.RLock()
+	layout := m.textLayoutLocked()
+	m.lock.RUnlock()
+	// 在阅读起点和终点之间居中
+	var x, y int32
+	if rotation == TrUp {
+		font.SetOrientation(900)
+		x = rect.Left + layout.offsetX + (rect.Width()-size.Cy)/2
+		y = rect.Bottom - layout.offsetY - startArea - (rect.Height()-startArea-endArea-size.Cx)/2
+	} else {
+		font.SetOrientation(2700)
+		x = rect.Left + layout.offsetX + (rect.Width()+size.Cy)/2
+		y = rect.Top + layout.offsetY + startArea + (rect.Height()-startArea-endArea-size.Cx)/2
+	}
+	canvas.TextOutWithIntX2Str(x, y, line)
+	font.SetOrientation(0)
+}
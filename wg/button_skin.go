@@ -0,0 +1,221 @@
+package wg
+
+import (
+	"bytes"
+	"errors"
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/widget/render"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// TSkinState 皮肤图片对应的按钮状态
+type TSkinState = int32
+
+const (
+	SkDefault  TSkinState = iota // 默认
+	SkEnter                      // 移入
+	SkDown                       // 按下
+	SkDisabled                   // 禁用
+	SkChecked                    // 选中
+	skStateCount
+)
+
+// 皮肤目录中各状态的图片文件名
+var skinFileNames = [skStateCount]string{"default.png", "enter.png", "down.png", "disabled.png", "checked.png"}
+
+// TInsets 九宫格边距
+type TInsets = render.Insets
+
+// TSliceMode 九宫格四边和中间的填充方式
+type TSliceMode = render.SliceMode
+
+const (
+	SmStretch = render.SliceStretch // 拉伸
+	SmTile    = render.SliceTile    // 平铺
+)
+
+// TButtonSkin 按钮九宫格皮肤, 设置后代替渐变背景, 文字和图标绘制在皮肤上面
+//
+//	每个状态一张图片, 缺少的状态使用: 选中 -> 按下 -> 默认, 其它 -> 默认
+//	SetSkin 时复制皮肤, 之后修改不影响已设置的按钮
+type TButtonSkin struct {
+	Insets TInsets    // 九宫格边距
+	Mode   TSliceMode // 四边和中间拉伸或平铺
+	images [skStateCount]*image.NRGBA
+}
+
+// NewButtonSkin 创建空皮肤
+func NewButtonSkin(insets TInsets, mode TSliceMode) *TButtonSkin {
+	return &TButtonSkin{Insets: insets, Mode: mode}
+}
+
+// LoadButtonSkinFS 从文件系统目录加载皮肤, 可以是 embed.FS 或 os.DirFS
+//
+//	目录中的文件: default.png(必需), enter.png, down.png, disabled.png, checked.png
+func LoadButtonSkinFS(fsys fs.FS, dir string, insets TInsets, mode TSliceMode) (*TButtonSkin, error) {
+	skin := NewButtonSkin(insets, mode)
+	for i, name := range skinFileNames {
+		state := TSkinState(i)
+		err := skin.LoadFromFS(fsys, state, path.Join(dir, name))
+		if err != nil && (state == SkDefault || !errors.Is(err, fs.ErrNotExist)) {
+			return nil, err
+		}
+	}
+	return skin, nil
+}
+
+// SetImage 设置状态图片, nil 清除
+func (m *TButtonSkin) SetImage(state TSkinState, img image.Image) {
+	if state < 0 || state >= skStateCount {
+		return
+	}
+	if img == nil {
+		m.images[state] = nil
+		return
+	}
+	m.images[state] = render.ToNRGBA(img)
+}
+
+// LoadFromFile 从文件加载状态图片
+func (m *TButtonSkin) LoadFromFile(state TSkinState, filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return m.LoadFromBytes(state, data)
+}
+
+// LoadFromBytes 从 PNG 数据加载状态图片
+func (m *TButtonSkin) LoadFromBytes(state TSkinState, data []byte) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	m.SetImage(state, img)
+	return nil
+}
+
+// LoadFromFS 从文件系统加载状态图片, 可以是 embed.FS
+func (m *TButtonSkin) LoadFromFS(fsys fs.FS, state TSkinState, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	return m.LoadFromBytes(state, data)
+}
+
+// image 返回状态图片, 缺少时按回退顺序查找
+func (m *TButtonSkin) image(state TSkinState) *image.NRGBA {
+	if img := m.images[state]; img != nil {
+		return img
+	}
+	if state == SkChecked && m.images[SkDown] != nil {
+		return m.images[SkDown]
+	}
+	return m.images[SkDefault]
+}
+
+// SetSkin 设置九宫格皮肤, nil 恢复渐变背景
+func (m *TButton) SetSkin(skin *TButtonSkin) {
+	m.lock.Lock()
+	if skin == nil {
+		m.skin = nil
+	} else {
+		c := *skin
+		m.skin = &c
+	}
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// Skin 返回按钮的皮肤, 没有时返回 nil
+func (m *TButton) Skin() *TButtonSkin {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.skin == nil {
+		return nil
+	}
+	c := *m.skin
+	return &c
+}
+
+// skinImage 返回当前状态的皮肤图片, 没有皮肤时返回 nil, 调用方持有锁
+func (m *TButton) skinImage() (*image.NRGBA, *TButtonSkin) {
+	if m.skin == nil {
+		return nil, nil
+	}
+	state := SkDefault
+	switch m.buttonState {
+	case BsEnter:
+		state = SkEnter
+	case BsDown:
+		state = SkDown
+	case BsDisabled:
+		state = SkDisabled
+	default:
+		if m.checked {
+			state = SkChecked
+		}
+	}
+	img := m.skin.image(state)
+	if img == nil {
+		return nil, nil
+	}
+	return img, m.skin
+}
+
+// skinKey 皮肤位图缓存键, 图片和大小不变时不重新绘制
+type skinKey struct {
+	src    *image.NRGBA
+	insets TInsets
+	mode   TSliceMode
+	width  int32
+	height int32
+}
+
+// tSkinPaint 按钮当前的皮肤位图, 只在主线程访问
+type tSkinPaint struct {
+	key    skinKey
+	img    lcl.ILazIntfImage
+	bitMap lcl.IBitmap
+	buf    *image.NRGBA
+}
+
+// skinBitmap 返回皮肤图片按九宫格绘制到指定大小的位图, 在主线程执行
+func (m *TButton) skinBitmap(src *image.NRGBA, skin *TButtonSkin, w, h int32) lcl.IBitmap {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	key := skinKey{src: src, insets: skin.Insets, mode: skin.Mode, width: w, height: h}
+	if m.skinPaint == nil {
+		m.skinPaint = &tSkinPaint{
+			img:    lcl.NewLazIntfImageWithIntX2RIQFlags(0, 0, types.NewSet(types.RiqfRGB, types.RiqfAlpha)),
+			bitMap: lcl.NewBitmap(),
+		}
+		m.skinPaint.bitMap.SetPixelFormat(types.Pf32bit)
+	}
+	p := m.skinPaint
+	if p.key == key {
+		return p.bitMap
+	}
+	p.key = key
+	if p.buf == nil || p.buf.Rect.Dx() != int(w) || p.buf.Rect.Dy() != int(h) {
+		p.buf = image.NewNRGBA(image.Rect(0, 0, int(w), int(h)))
+		p.img.SetSize(w, h)
+		p.bitMap.SetSize(w, h)
+	}
+	render.NineSlice(p.buf, src, skin.Insets, skin.Mode)
+	loadNRGBA(p.img, p.buf)
+	p.bitMap.LoadFromIntfImage(p.img)
+	return p.bitMap
+}
+
+func (m *tSkinPaint) free() {
+	m.img.Free()
+	m.bitMap.Free()
+}
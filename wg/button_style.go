@@ -0,0 +1,229 @@
+package wg
+
+import (
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"sync"
+)
+
+// TButtonVariant 按钮内置样式
+type TButtonVariant = int32
+
+const (
+	BvCustom    TButtonVariant = iota // 自定义(默认), 不应用内置样式
+	BvPrimary                         // 主要按钮, 主色填充
+	BvSecondary                       // 次要按钮, 次要色填充
+	BvOutline                         // 描边按钮, 背景色填充, 主色边框和文字
+	BvGhost                           // 幽灵按钮, 无边框, 背景透明, 移入时显示浅色背景
+	BvDanger                          // 危险按钮, 危险色填充
+	BvLink                            // 链接按钮, 背景透明, 只显示主色文字, 移入时显示下划线
+)
+
+// TButtonSize 按钮尺寸预设
+type TButtonSize = int32
+
+const (
+	BszCustom TButtonSize = iota // 自定义(默认), 不应用尺寸预设
+	BszSmall                     // 小
+	BszMedium                    // 中
+	BszLarge                     // 大
+)
+
+// 尺寸预设: 字体大小, 高度, 左右内边距, 圆角
+type buttonSizePreset struct {
+	fontSize int32
+	height   int32
+	padding  int32
+	radius   int32
+}
+
+var buttonSizePresets = map[TButtonSize]buttonSizePreset{
+	BszSmall:  {fontSize: 9, height: 28, padding: 8, radius: 4},
+	BszMedium: {fontSize: 10, height: 36, padding: 12, radius: 6},
+	BszLarge:  {fontSize: 12, height: 44, padding: 16, radius: 8},
+}
+
+// TPalette 调色板, 内置样式的颜色来源
+type TPalette struct {
+	Primary      colors.TColor // 主色
+	Secondary    colors.TColor // 次要色
+	Danger       colors.TColor // 危险色
+	Background   colors.TColor // 背景色, 描边按钮的底色, 幽灵按钮移入/按下颜色的基准
+	Text         colors.TColor // 背景色上的文字颜色
+	TextOnFill   colors.TColor // 填充色上的文字颜色
+	Disabled     colors.TColor // 禁用底色
+	DisabledText colors.TColor // 禁用文字颜色
+}
+
+// DefaultPalette 返回默认调色板
+func DefaultPalette() TPalette {
+	return TPalette{
+		Primary:      defaultButtonColor,
+		Secondary:    colors.RGBToColor(108, 117, 125),
+		Danger:       colors.RGBToColor(220, 53, 69),
+		Background:   colors.RGBToColor(255, 255, 255),
+		Text:         colors.RGBToColor(33, 37, 41),
+		TextOnFill:   colors.RGBToColor(255, 255, 255),
+		Disabled:     defaultButtonColorDisable,
+		DisabledText: colors.RGBToColor(120, 120, 120),
+	}
+}
+
+// 当前使用的调色板
+var (
+	activePalette     = DefaultPalette()
+	activePaletteLock sync.RWMutex
+)
+
+// SetPalette 设置当前调色板
+//
+//	只影响之后调用 SetVariant 的按钮, 已应用样式的按钮需要重新调用 SetVariant
+func SetPalette(palette TPalette) {
+	activePaletteLock.Lock()
+	defer activePaletteLock.Unlock()
+	activePalette = palette
+}
+
+// Palette 返回当前调色板
+func Palette() TPalette {
+	activePaletteLock.RLock()
+	defer activePaletteLock.RUnlock()
+	return activePalette
+}
+
+// SetVariant 设置按钮内置样式, 颜色从当前调色板派生
+//
+//	默认, 移入, 按下, 禁用四种状态的背景色, 边框和文字颜色一次设置完成
+//	幽灵和链接按钮的透明状态使用 ClNone 作为背景色, 不填充背景
+func (m *TButton) SetVariant(variant TButtonVariant) {
+	m.lock.Lock()
+	m.variant = variant
+	m.lock.Unlock()
+	if variant == BvCustom {
+		return
+	}
+	runOnMainThread(func() {
+		m.applyVariant(variant)
+	})
+}
+
+// applyVariant 应用内置样式, 在主线程执行
+func (m *TButton) applyVariant(variant TButtonVariant) {
+	p := Palette()
+	font := m.Font()
+	font.SetStyle(font.Style().Exclude(types.FsUnderline))
+	switch variant {
+	case BvPrimary, BvSecondary, BvDanger:
+		fill := p.Primary
+		if variant == BvSecondary {
+			fill = p.Secondary
+		} else if variant == BvDanger {
+			fill = p.Danger
+		}
+		m.SetColor(fill)
+		m.SetBorderDirections(types.NewSet())
+		font.SetColor(p.TextOnFill)
+	case BvOutline:
+		m.SetDefaultColor(p.Background, p.Background)
+		enter := shadeColor(p.Background, 0.08)
+		down := shadeColor(p.Background, 0.16)
+		m.SetEnterColor(enter, enter)
+		m.SetDownColor(down, down)
+		m.SetBorderDirections(types.NewSet(BbdLeft, BbdTop, BbdRight, BbdBottom))
+		m.SetBorderWidth(BbdNone, 1)
+		m.SetBorderColor(BbdNone, p.Primary)
+		font.SetColor(p.Primary)
+	case BvGhost:
+		m.SetDefaultColor(colors.ClNone, colors.ClNone)
+		enter := shadeColor(p.Background, 0.08)
+		down := shadeColor(p.Background, 0.16)
+		m.SetEnterColor(enter, enter)
+		m.SetDownColor(down, down)
+		m.SetBorderDirections(types.NewSet())
+		font.SetColor(p.Text)
+	case BvLink:
+		m.SetDefaultColor(colors.ClNone, colors.ClNone)
+		m.SetEnterColor(colors.ClNone, colors.ClNone)
+		m.SetDownColor(colors.ClNone, colors.ClNone)
+		m.SetBorderDirections(types.NewSet())
+		font.SetColor(p.Primary)
+	}
+	switch variant {
+	case BvOutline:
+		m.SetDisabledColor(p.Background, p.Background)
+		m.setDisabledBorder(1, p.Disabled)
+	case BvGhost, BvLink:
+		m.SetDisabledColor(colors.ClNone, colors.ClNone)
+	default:
+		m.SetDisabledColor(p.Disabled, p.Disabled)
+	}
+	m.SetDisabledTextColor(p.DisabledText)
+	m.Invalidate()
+}
+
+// setDisabledBorder 设置禁用状态的边框宽度和颜色
+//
+//	SetBorderWidth 和 SetBorderColor 不影响禁用状态
+func (m *TButton) setDisabledBorder(width int32, color colors.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.disabledColor.SetBorderWidth(BbdNone, width)
+	m.disabledColor.SetBorderColor(BbdNone, color)
+}
+
+// SetDisabledTextColor 设置禁用状态的文字颜色, ClNone(默认)时使用字体颜色
+func (m *TButton) SetDisabledTextColor(color colors.TColor) {
+	m.lock.Lock()
+	m.disabledTextColor = color
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// DisabledTextColor 返回禁用状态的文字颜色
+func (m *TButton) DisabledTextColor() colors.TColor {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.disabledTextColor
+}
+
+// Variant 返回按钮内置样式
+func (m *TButton) Variant() TButtonVariant {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.variant
+}
+
+// SetSizePreset 设置按钮尺寸预设, 同时设置字体大小, 高度, 内边距和圆角
+func (m *TButton) SetSizePreset(size TButtonSize) {
+	preset, ok := buttonSizePresets[size]
+	m.lock.Lock()
+	m.sizePreset = size
+	if ok {
+		m.padding = preset.padding
+	}
+	m.lock.Unlock()
+	if !ok {
+		return
+	}
+	m.SetRadius(preset.radius)
+	runOnMainThread(func() {
+		m.Font().SetSize(preset.fontSize)
+		m.SetHeight(preset.height)
+		m.AutoSizeWidth()
+	})
+}
+
+// SizePreset 返回按钮尺寸预设
+func (m *TButton) SizePreset() TButtonSize {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.sizePreset
+}
+
+// shadeColor 根据颜色亮度选择暗化或亮化, 用于背景色上的移入/按下效果
+func shadeColor(color colors.TColor, factor float64) colors.TColor {
+	if colors.Red(GrayColor(color)) > 127 {
+		return DarkenColor(color, factor)
+	}
+	return LightenColor(color, factor)
+}
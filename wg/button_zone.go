@@ -0,0 +1,473 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+)
+
+// TIconVisibility 动作图标显示策略
+type TIconVisibility = int32
+
+const (
+	IvAlways TIconVisibility = iota // 一直显示
+	IvHover                         // 鼠标移入按钮时显示, 不显示时仍占用位置, 文字不会跳动
+)
+
+// CloseZone 关闭图标区域名称, 按钮创建时添加, SetIconClose 等方法作用于该区域
+const CloseZone = "close"
+
+// TIconZone 按钮右侧的动作图标区域
+//
+//	多个区域从左到右排列在按钮右侧, 关闭区域默认在最右
+//	每个区域有自己的图标, 移入高亮图标, 字体图标, 提示, 光标, 显示策略和点击事件
+//	点击图标不改变按钮状态, 按钮的 OnClick 仍会触发, 可以用 EnteredZone 区分
+type TIconZone struct {
+	button     *TButton
+	name       string
+	icon       lcl.IPicture  // 图标, 只在主线程访问
+	highlight  lcl.IPicture  // 移入高亮图标, 为空时使用 icon, 只在主线程访问
+	glyph      *TIconGlyph   // 字体图标, 没有图片时绘制
+	hoverColor colors.TColor // 移入时字体图标的颜色, ClDefault 使用默认颜色
+	hint       string
+	cursor     types.TCursor
+	visibility TIconVisibility
+	hidden     bool
+	onClick    lcl.TNotifyEvent
+}
+
+// tZoneRect 动作图标在按钮中的位置
+type tZoneRect struct {
+	zone *TIconZone
+	rect types.TRect
+}
+
+// newIconZone 创建动作图标区域, 图片在加入按钮时由 createPictures 创建
+func newIconZone(button *TButton, name string) *TIconZone {
+	return &TIconZone{button: button, name: name, cursor: types.CrDefault, hoverColor: colors.ClDefault}
+}
+
+// createPictures 创建图标和移入高亮图标, 在主线程执行
+func (z *TIconZone) createPictures() {
+	z.icon = lcl.NewPicture()
+	z.highlight = lcl.NewPicture()
+	z.icon.SetOnChange(z.button.zoneIconChange)
+	z.highlight.SetOnChange(z.button.zoneIconChange)
+}
+
+func (z *TIconZone) free() {
+	z.icon.SetOnChange(nil)
+	z.highlight.SetOnChange(nil)
+	z.icon.Free()
+	z.highlight.Free()
+}
+
+// Name 返回区域名称
+func (z *TIconZone) Name() string {
+	return z.name
+}
+
+// Button 返回区域所在按钮
+func (z *TIconZone) Button() *TButton {
+	return z.button
+}
+
+// SetIcon 从文件加载图标
+func (z *TIconZone) SetIcon(filePath string) {
+	z.load(func() lcl.IPicture { return z.icon }, func(picture lcl.IPicture) { picture.LoadFromFile(filePath) })
+}
+
+// SetIconFormBytes 从 PNG 数据加载图标, nil 清空
+func (z *TIconZone) SetIconFormBytes(pngData []byte) {
+	z.load(func() lcl.IPicture { return z.icon }, func(picture lcl.IPicture) { loadPictureFromBytes(picture, pngData) })
+}
+
+// SetHighlight 从文件加载移入高亮图标
+func (z *TIconZone) SetHighlight(filePath string) {
+	z.load(func() lcl.IPicture { return z.highlight }, func(picture lcl.IPicture) { picture.LoadFromFile(filePath) })
+}
+
+// SetHighlightFormBytes 从 PNG 数据加载移入高亮图标, nil 清空
+func (z *TIconZone) SetHighlightFormBytes(pngData []byte) {
+	z.load(func() lcl.IPicture { return z.highlight }, func(picture lcl.IPicture) { loadPictureFromBytes(picture, pngData) })
+}
+
+// load 在主线程加载图片, 之后重新计算按钮宽度
+func (z *TIconZone) load(picture func() lcl.IPicture, fn func(picture lcl.IPicture)) {
+	runOnMainThread(func() {
+		if !z.button.IsValid() || !z.button.hasZone(z) {
+			return
+		}
+		fn(picture())
+		z.button.AutoSizeWidth()
+	})
+}
+
+// SetGlyph 设置字体图标, 没有图片时绘制, nil 清除
+func (z *TIconZone) SetGlyph(glyph *TIconGlyph) {
+	z.button.lock.Lock()
+	z.glyph = copyGlyph(glyph)
+	z.button.lock.Unlock()
+	z.button.AutoSizeWidth()
+}
+
+// SetHoverColor 设置鼠标移入时字体图标的颜色
+//
+//	colors.ClDefault 使用默认颜色: 关闭图标为调色板的危险色, 其它图标按按下状态加深
+func (z *TIconZone) SetHoverColor(color colors.TColor) {
+	z.button.lock.Lock()
+	z.hoverColor = color
+	z.button.lock.Unlock()
+	z.button.invalidate()
+}
+
+// HoverColor 返回鼠标移入时字体图标的颜色
+func (z *TIconZone) HoverColor() colors.TColor {
+	z.button.lock.RLock()
+	defer z.button.lock.RUnlock()
+	return z.hoverColor
+}
+
+// SetHint 设置鼠标停留在图标上时显示的提示, 空字符串不显示
+func (z *TIconZone) SetHint(hint string) {
+	z.button.lock.Lock()
+	defer z.button.lock.Unlock()
+	z.hint = hint
+}
+
+// Hint 返回提示
+func (z *TIconZone) Hint() string {
+	z.button.lock.RLock()
+	defer z.button.lock.RUnlock()
+	return z.hint
+}
+
+// SetCursor 设置鼠标在图标上时的光标
+func (z *TIconZone) SetCursor(cursor types.TCursor) {
+	z.button.lock.Lock()
+	defer z.button.lock.Unlock()
+	z.cursor = cursor
+}
+
+// Cursor 返回鼠标在图标上时的光标
+func (z *TIconZone) Cursor() types.TCursor {
+	z.button.lock.RLock()
+	defer z.button.lock.RUnlock()
+	return z.cursor
+}
+
+// SetVisibility 设置显示策略
+func (z *TIconZone) SetVisibility(visibility TIconVisibility) {
+	z.button.lock.Lock()
+	z.visibility = visibility
+	z.button.lock.Unlock()
+	z.button.invalidate()
+}
+
+// Visibility 返回显示策略
+func (z *TIconZone) Visibility() TIconVisibility {
+	z.button.lock.RLock()
+	defer z.button.lock.RUnlock()
+	return z.visibility
+}
+
+// SetVisible 设置是否显示, 隐藏后不占用位置
+func (z *TIconZone) SetVisible(visible bool) {
+	z.button.lock.Lock()
+	z.hidden = !visible
+	z.button.lock.Unlock()
+	z.button.AutoSizeWidth()
+}
+
+// Visible 返回是否显示
+func (z *TIconZone) Visible() bool {
+	z.button.lock.RLock()
+	defer z.button.lock.RUnlock()
+	return !z.hidden
+}
+
+// SetOnClick 设置图标点击事件, sender 为按钮
+func (z *TIconZone) SetOnClick(fn lcl.TNotifyEvent) {
+	z.button.lock.Lock()
+	defer z.button.lock.Unlock()
+	z.onClick = fn
+}
+
+// size 返回图标大小, 没有图片时使用字体图标, 在主线程执行
+func (z *TIconZone) size(canvas lcl.ICanvas) (width, height int32) {
+	if z.icon.Width() > 0 {
+		return z.icon.Width(), z.icon.Height()
+	}
+	z.button.lock.RLock()
+	glyph := z.glyph
+	z.button.lock.RUnlock()
+	if glyph != nil {
+		return measureGlyph(canvas, glyph)
+	}
+	return 0, 0
+}
+
+// AddIconZone 添加动作图标区域, 插入在关闭区域左侧, 名称已存在时返回已有区域
+//
+//	区域在主线程加入按钮, 返回的区域可以立即设置, 设置按调用顺序在加入后生效
+//	加入之前 IconZone 和 IconZones 不包含该区域
+func (m *TButton) AddIconZone(name string) *TIconZone {
+	return m.insertIconZone(name, func() int {
+		for i, z := range m.zones {
+			if z.name == CloseZone {
+				return i
+			}
+		}
+		return len(m.zones)
+	})
+}
+
+// InsertIconZone 在 index 位置插入动作图标区域, 0 为最左, 名称已存在时返回已有区域
+//
+//	区域在主线程加入按钮, 同 AddIconZone
+func (m *TButton) InsertIconZone(index int, name string) *TIconZone {
+	return m.insertIconZone(name, func() int {
+		return index
+	})
+}
+
+// insertIconZone 在主线程把区域插入到 index 返回的位置, index 在持有锁时调用
+func (m *TButton) insertIconZone(name string, index func() int) *TIconZone {
+	if z := m.IconZone(name); z != nil {
+		return z
+	}
+	z := newIconZone(m, name)
+	runOnMainThread(func() {
+		// 加入之前同名区域已经加入时丢弃
+		if !m.IsValid() || m.IconZone(name) != nil {
+			return
+		}
+		z.createPictures()
+		m.lock.Lock()
+		i := index()
+		if i < 0 {
+			i = 0
+		} else if i > len(m.zones) {
+			i = len(m.zones)
+		}
+		m.zones = append(m.zones, nil)
+		copy(m.zones[i+1:], m.zones[i:])
+		m.zones[i] = z
+		m.lock.Unlock()
+		m.AutoSizeWidth()
+	})
+	return z
+}
+
+// RemoveIconZone 删除动作图标区域, 在主线程删除并释放图片
+func (m *TButton) RemoveIconZone(name string) {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.lock.Lock()
+		var removed *TIconZone
+		for i, z := range m.zones {
+			if z.name == name {
+				removed = z
+				m.zones = append(m.zones[:i:i], m.zones[i+1:]...)
+				break
+			}
+		}
+		if removed != nil && m.enterZone == removed {
+			m.enterZone = nil
+		}
+		m.lock.Unlock()
+		if removed == nil {
+			return
+		}
+		removed.free()
+		m.AutoSizeWidth()
+	})
+}
+
+// IconZone 返回指定名称的动作图标区域, 不存在时返回 nil
+func (m *TButton) IconZone(name string) *TIconZone {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, z := range m.zones {
+		if z.name == name {
+			return z
+		}
+	}
+	return nil
+}
+
+// IconZones 返回全部动作图标区域, 从左到右
+func (m *TButton) IconZones() []*TIconZone {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return append([]*TIconZone(nil), m.zones...)
+}
+
+// EnteredZone 返回鼠标所在的动作图标区域, 没有时返回 nil
+func (m *TButton) EnteredZone() *TIconZone {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.enterZone
+}
+
+// ZoneAt 返回控件坐标 X, Y 所在的动作图标区域, 没有时返回 nil
+func (m *TButton) ZoneAt(X, Y int32) *TIconZone {
+	if m.Disable() || !m.IsValid() {
+		return nil
+	}
+	m.lock.RLock()
+	hovering := m.buttonState == BsEnter || m.buttonState == BsDown
+	m.lock.RUnlock()
+	pt := types.TPoint{X: X, Y: Y}
+	for _, zr := range m.zoneRects(m.Canvas(), m.ClientRect()) {
+		if zr.zone.Visibility() == IvHover && !hovering {
+			continue
+		}
+		// 包含右下边界
+		r := zr.rect
+		if pt.X >= r.Left && pt.X <= r.Right && pt.Y >= r.Top && pt.Y <= r.Bottom {
+			return zr.zone
+		}
+	}
+	return nil
+}
+
+// hasZone 区域是否仍属于按钮
+func (m *TButton) hasZone(zone *TIconZone) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, z := range m.zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// closeZone 返回关闭图标区域
+func (m *TButton) closeZone() *TIconZone {
+	return m.IconZone(CloseZone)
+}
+
+// zoneRects 计算显示的动作图标位置, 从右向左排列, 在主线程执行
+//
+//	文字旋转时沿纵向从阅读终点向起点排列
+func (m *TButton) zoneRects(canvas lcl.ICanvas, rect types.TRect) []tZoneRect {
+	// 只显示前置图标时不显示动作图标
+	if m.isIconOnly() {
+		return nil
+	}
+	zones := m.IconZones()
+	rotation := m.TextRotation()
+	var result []tZoneRect
+	x := rect.Left + rect.Width() - iconMargin
+	y := rect.Top + iconMargin
+	if rotation == TrDown {
+		y = rect.Bottom - iconMargin
+	}
+	for i := len(zones) - 1; i >= 0; i-- {
+		z := zones[i]
+		if !z.Visible() {
+			continue
+		}
+		w, h := z.size(canvas)
+		if w <= 0 {
+			continue
+		}
+		var zr types.TRect
+		switch rotation {
+		case TrUp:
+			zr = types.TRect{Left: rect.Left + rect.Width()/2 - w/2, Top: y, Bottom: y + h}
+			y += h + iconMargin
+		case TrDown:
+			y -= h
+			zr = types.TRect{Left: rect.Left + rect.Width()/2 - w/2, Top: y, Bottom: y + h}
+			y -= iconMargin
+		default:
+			x -= w
+			zr = types.TRect{Left: x, Top: rect.Top + rect.Height()/2 - h/2}
+			zr.Bottom = zr.Top + h
+			x -= iconMargin
+		}
+		zr.Right = zr.Left + w
+		result = append(result, tZoneRect{zone: z, rect: zr})
+	}
+	return result
+}
+
+// zonesWidth 返回动作图标沿文字方向占用的长度, 包括边距和图标与文本间距
+func (m *TButton) zonesWidth(canvas lcl.ICanvas) int32 {
+	rects := m.zoneRects(canvas, types.TRect{})
+	if len(rects) == 0 {
+		return 0
+	}
+	rotated := m.TextRotation() != TrNone
+	width := int32(iconMargin)
+	for _, zr := range rects {
+		if rotated {
+			width += zr.rect.Height() + iconMargin
+		} else {
+			width += zr.rect.Width() + iconMargin
+		}
+	}
+	return width
+}
+
+// setEnterZone 设置鼠标所在的动作图标, 返回是否发生变化
+func (m *TButton) setEnterZone(zone *TIconZone) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.enterZone == zone {
+		return false
+	}
+	m.enterZone = zone
+	return true
+}
+
+// drawZones 绘制动作图标, 在主线程执行
+func (m *TButton) drawZones(canvas lcl.ICanvas, rect types.TRect, textColor colors.TColor) {
+	m.lock.RLock()
+	state, enterZone := m.buttonState, m.enterZone
+	m.lock.RUnlock()
+	hovering := state == BsEnter || state == BsDown
+	for _, zr := range m.zoneRects(canvas, rect) {
+		z := zr.zone
+		m.lock.RLock()
+		visibility, glyph, hoverColor := z.visibility, z.glyph, z.hoverColor
+		m.lock.RUnlock()
+		if visibility == IvHover && !hovering {
+			continue
+		}
+		entered := z == enterZone
+		if z.icon.Width() > 0 {
+			picture := z.icon
+			if entered && z.highlight.Width() > 0 {
+				picture = z.highlight
+			}
+			canvas.DrawWithIntX2Graphic(zr.rect.Left, zr.rect.Top, picture.Graphic())
+		} else if glyph != nil {
+			// 移入时使用区域的移入颜色, 默认关闭图标使用危险色, 其它图标按按下状态加深
+			glyphState, highlight := state, colors.ClNone
+			if entered {
+				highlight = hoverColor
+				if highlight == colors.ClDefault && z.name == CloseZone {
+					highlight = Palette().Danger
+				} else if highlight == colors.ClDefault {
+					glyphState, highlight = BsDown, colors.ClNone
+				}
+			}
+			color := m.glyphColor(glyph, textColor, glyphState, highlight)
+			drawGlyph(canvas, glyph, zr.rect.Left, zr.rect.Top, color)
+		}
+	}
+}
+
+// zoneIconChange 动作图标图片变化
+func (m *TButton) zoneIconChange(sender lcl.IObject) {
+	if !m.IsValid() {
+		return
+	}
+	m.Invalidate()
+}
@@ -0,0 +1,117 @@
+package wg
+
+import (
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/render"
+	"image/color"
+)
+
+// 颜色工具: HSL, HSV, OKLCH 转换, 感知亮化/暗化, 混合, 对比度, 可读文字颜色
+//
+//	计算在 render 包中完成, 这里只转换 TColor
+//	颜色参数为 RGB 颜色, 系统颜色(如 Cl3DFace)没有 RGB 分量, 需要先转换
+
+// THSL 色相(0-360), 饱和度(0-1), 亮度(0-1)
+type THSL = render.HSL
+
+// THSV 色相(0-360), 饱和度(0-1), 明度(0-1)
+type THSV = render.HSV
+
+// TOKLCH OKLCH 感知颜色空间, 亮度(0-1), 色度(0-约0.4), 色相(0-360)
+//
+//	相同的亮度差在不同色相上看起来变化相同, 适合做亮化/暗化
+type TOKLCH = render.OKLCH
+
+// ColorToHSL 转换颜色为 HSL
+func ColorToHSL(color types.TColor) THSL {
+	return render.ToHSL(ColorToNRGBA(color))
+}
+
+// HSLToColor 转换 HSL 为颜色
+func HSLToColor(hsl THSL) types.TColor {
+	return nrgbaToColor(render.FromHSL(hsl))
+}
+
+// ColorToHSV 转换颜色为 HSV
+func ColorToHSV(color types.TColor) THSV {
+	return render.ToHSV(ColorToNRGBA(color))
+}
+
+// HSVToColor 转换 HSV 为颜色
+func HSVToColor(hsv THSV) types.TColor {
+	return nrgbaToColor(render.FromHSV(hsv))
+}
+
+// ColorToOKLCH 转换颜色为 OKLCH
+func ColorToOKLCH(color types.TColor) TOKLCH {
+	return render.ToOKLCH(ColorToNRGBA(color))
+}
+
+// OKLCHToColor 转换 OKLCH 为颜色
+//
+//	超出 sRGB 范围时保持亮度和色相, 降低色度
+func OKLCHToColor(lch TOKLCH) types.TColor {
+	return nrgbaToColor(render.FromOKLCH(lch))
+}
+
+// LightenPerceptual 在 OKLCH 空间提高亮度
+//
+//	amount: 亮度增量, 取值 0.0-1.0, 与 LightenColor 不同, 各色相看起来变化相同
+func LightenPerceptual(color types.TColor, amount float64) types.TColor {
+	return nrgbaToColor(render.LightenPerceptual(ColorToNRGBA(color), amount))
+}
+
+// DarkenPerceptual 在 OKLCH 空间降低亮度
+//
+//	amount: 亮度减量, 取值 0.0-1.0
+func DarkenPerceptual(color types.TColor, amount float64) types.TColor {
+	return LightenPerceptual(color, -amount)
+}
+
+// MixColor 在线性 RGB 空间混合两种颜色
+//
+//	t: 0.0 返回 a, 1.0 返回 b
+func MixColor(a, b types.TColor, t float64) types.TColor {
+	return nrgbaToColor(render.Mix(ColorToNRGBA(a), ColorToNRGBA(b), t))
+}
+
+// RelativeLuminance 返回 WCAG 相对亮度, 0.0(黑) - 1.0(白)
+func RelativeLuminance(color types.TColor) float64 {
+	return render.RelativeLuminance(ColorToNRGBA(color))
+}
+
+// ContrastRatio 返回两种颜色的 WCAG 对比度, 1 - 21
+//
+//	正文文字建议不低于 4.5, 大号文字不低于 3
+func ContrastRatio(a, b types.TColor) float64 {
+	return render.ContrastRatio(ColorToNRGBA(a), ColorToNRGBA(b))
+}
+
+// ReadableTextColor 返回在背景色上对比度最高的文字颜色
+//
+//	candidates: 候选颜色, 为空时在黑色和白色中选择
+func ReadableTextColor(background types.TColor, candidates ...types.TColor) types.TColor {
+	return readableTextColorOn([]types.TColor{background}, candidates)
+}
+
+// readableTextColorOn 返回在所有背景色上最低对比度最高的文字颜色, 用于渐变背景
+func readableTextColorOn(backgrounds, candidates []types.TColor) types.TColor {
+	if len(candidates) == 0 {
+		candidates = []types.TColor{colors.ClBlack, colors.ClWhite}
+	}
+	return candidates[render.Readable(colorsToNRGBA(backgrounds), colorsToNRGBA(candidates))]
+}
+
+// nrgbaToColor 转换 color.NRGBA 为 TColor, 透明度忽略
+func nrgbaToColor(c color.NRGBA) types.TColor {
+	return colors.RGBToColor(c.R, c.G, c.B)
+}
+
+func colorsToNRGBA(list []types.TColor) []color.NRGBA {
+	result := make([]color.NRGBA, len(list))
+	for i, c := range list {
+		result[i] = ColorToNRGBA(c)
+	}
+	return result
+}
@@ -6,18 +6,29 @@ import (
 	"github.com/energye/lcl/types"
 	"github.com/energye/lcl/types/colors"
 	"github.com/energye/lcl/types/messages"
+	"github.com/energye/widget/render"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type IGraphicControl = lcl.ICustomGraphicControl
 
+// TInput 自绘输入框
+//
+//	在其它 goroutine 修改文本和颜色时使用 SetText, SetTextColor, SetBackgroundColor
 type TInput struct {
 	IGraphicControl
-	Text            string
+	lock            sync.RWMutex // 保护 Text, TextColor, BackgroundColor, opacity
+	Text            string       // 只能在主线程直接修改, 其它 goroutine 使用 SetText
 	TextColor       colors.TColor
 	BackgroundColor colors.TColor
 	Edit            lcl.IEdit
+	// 整体不透明度
+	opacity byte
+	fadeSeq atomic.Uint64
+	layer   *tLayer // 半透明绘制时使用的离屏图像, 只在主线程访问
 }
 
 func NewInput(owner lcl.IWinControl) *TInput {
@@ -29,13 +40,21 @@ func NewInput(owner lcl.IWinControl) *TInput {
 	m.Edit.SetLeft(-200)
 	m.TextColor = colors.ClBlack
 	m.BackgroundColor = colors.ClWhite
+	m.opacity = 255
 	m.SetParentBackground(true)
 	m.SetParentColor(true)
 	m.Canvas().SetAntialiasingMode(types.AmOn)
 	m.SetControlStyle(m.ControlStyle().Include(types.CsParentBackground, types.CsFocusing))
+	m.SetAccessibleRole(types.LarTextEditorSingleline)
 	// 事件
 	m.IGraphicControl.SetOnPaint(m.paint)
 	m.IGraphicControl.SetOnWndProc(m.onWndProc)
+	m.IGraphicControl.SetOnDestroy(func() {
+		if m.layer != nil {
+			m.layer.free()
+			m.layer = nil
+		}
+	})
 	m.IGraphicControl.SetOnMouseDown(func(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
 		m.Edit.SetFocus()
 	})
@@ -51,12 +70,49 @@ func (m *TInput) drawBackground(canvas lcl.ICanvas) {
 
 func (m *TInput) drawText(canvas lcl.ICanvas) {
 	clientRect := m.ClientRect()
+	m.lock.RLock()
+	text, textColor := m.Text, m.TextColor
+	m.lock.RUnlock()
 	font := canvas.FontToFont()
-	font.SetColor(m.TextColor)
+	font.SetColor(textColor)
 	//brush := canvas.BrushToBrush()
 	//brush.SetColor(m.BackgroundColor)
-	canvas.TextOutWithIntX2Str(clientRect.Left, clientRect.Top, m.Text)
+	canvas.TextOutWithIntX2Str(clientRect.Left, clientRect.Top, text)
+
+}
+
+// SetText 设置文本并重绘
+func (m *TInput) SetText(text string) {
+	m.lock.Lock()
+	m.Text = text
+	m.lock.Unlock()
+	m.invalidate()
+	runOnMainThread(m.updateAccessible)
+}
+
+// SetTextColor 设置文本颜色并重绘
+func (m *TInput) SetTextColor(color colors.TColor) {
+	m.lock.Lock()
+	m.TextColor = color
+	m.lock.Unlock()
+	m.invalidate()
+}
 
+// SetBackgroundColor 设置背景颜色并重绘
+func (m *TInput) SetBackgroundColor(color colors.TColor) {
+	m.lock.Lock()
+	m.BackgroundColor = color
+	m.lock.Unlock()
+	m.invalidate()
+}
+
+// invalidate 在主线程重绘
+func (m *TInput) invalidate() {
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.Invalidate()
+		}
+	})
 }
 
 func (m *TInput) paint(sender lcl.IObject) {
@@ -64,8 +120,84 @@ func (m *TInput) paint(sender lcl.IObject) {
 		return
 	}
 	canvas := m.Canvas()
-	m.drawBackground(canvas)
-	m.drawText(canvas)
+	if opacity := m.Opacity(); opacity == 255 {
+		m.drawBackground(canvas)
+		m.drawText(canvas)
+	} else if opacity > 0 {
+		m.drawTranslucent(canvas, m.ClientRect(), opacity)
+	}
+}
+
+// drawTranslucent 按整体不透明度绘制输入框, 在主线程执行
+func (m *TInput) drawTranslucent(canvas lcl.ICanvas, rect types.TRect, opacity byte) {
+	w, h := rect.Width(), rect.Height()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if m.layer == nil {
+		m.layer = newLayer()
+	}
+	l := m.layer
+	l.resize(w, h)
+	// 背景和文字都在内容层中, 合成到透明底图上
+	for i := range l.background.Pix {
+		l.background.Pix[i] = 0
+	}
+	l.drawContent(canvas.FontToFont(), func(canvas lcl.ICanvas, rect types.TRect) {
+		m.drawBackground(canvas)
+		m.drawText(canvas)
+	})
+	render.Composite(l.background, l.content, nil, opacity)
+	l.present(canvas, rect)
+}
+
+// SetOpacity 设置整体不透明度 0 ~ 255, 会停止正在进行的渐变动画
+func (m *TInput) SetOpacity(opacity byte) {
+	m.fadeSeq.Add(1)
+	m.setOpacity(opacity)
+}
+
+// Opacity 返回整体不透明度
+func (m *TInput) Opacity() byte {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.opacity
+}
+
+// setOpacity 设置整体不透明度并重绘, 不影响渐变动画
+func (m *TInput) setOpacity(opacity byte) {
+	m.lock.Lock()
+	changed := m.opacity != opacity
+	m.opacity = opacity
+	m.lock.Unlock()
+	if changed {
+		m.invalidate()
+	}
+}
+
+// FadeIn 显示输入框, 在 duration 内不透明度从 0 变到 255, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时也调用 done, 不透明度保持打断时的值
+func (m *TInput) FadeIn(duration time.Duration, done func()) {
+	seq := m.fadeSeq.Add(1)
+	m.setOpacity(0)
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.SetVisible(true)
+		}
+	})
+	animateOpacity(&m.fadeSeq, seq, 0, 255, duration, m.setOpacity, fadeDone(done))
+}
+
+// FadeOut 在 duration 内不透明度变到 0 后隐藏输入框并恢复开始时的不透明度, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时不隐藏, 也调用 done, 见 fadeOut
+func (m *TInput) FadeOut(duration time.Duration, done func()) {
+	fadeOut(&m.fadeSeq, duration, m.Opacity, m.setOpacity, func() {
+		if m.IsValid() {
+			m.SetVisible(false)
+		}
+	}, done)
 }
 
 func (m *TInput) onWndProc(message *types.TLMessage) {
@@ -0,0 +1,54 @@
+//go:build lcl
+
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// 需要 liblcl 的测试使用 lcl 构建标签: go test -tags lcl ./wg
+// 不带标签时只运行不调用 LCL 的测试, 例如缓存和键盘逻辑
+//
+// 测试用例在其它 goroutine 中运行, 主线程处理 onMain 提交的函数和 LCL 消息
+
+var mainFuncs = make(chan func())
+
+func init() {
+	// LCL 只能在启动时的系统线程中使用
+	runtime.LockOSThread()
+}
+
+func TestMain(m *testing.M) {
+	lcl.Init(nil, nil)
+	lcl.Application.Initialize()
+	done := make(chan int)
+	go func() {
+		done <- m.Run()
+	}()
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case code := <-done:
+			os.Exit(code)
+		case fn := <-mainFuncs:
+			fn()
+		case <-ticker.C:
+			lcl.Application.ProcessMessages()
+		}
+	}
+}
+
+// onMain 在主线程执行 fn 并等待完成
+func onMain(fn func()) {
+	done := make(chan struct{})
+	mainFuncs <- func() {
+		defer close(done)
+		fn()
+	}
+	<-done
+}
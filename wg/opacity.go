@@ -0,0 +1,278 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"github.com/energye/widget/render"
+	"image"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// 渐变动画帧间隔
+const fadeFrameInterval = time.Second / 60
+
+// tLayer 半透明绘制使用的离屏图像
+//
+//	文字和图标等 LCL 绘制的内容分别画在黑色和白色底图上, 读回后由两次结果的差恢复内容层的透明度,
+//	再合成到 Go 绘制的背景上, 整体乘以不透明度后画到控件画布
+type tLayer struct {
+	onBlack    lcl.IBitmap
+	onWhite    lcl.IBitmap
+	result     lcl.IBitmap
+	img        lcl.ILazIntfImage
+	background *image.NRGBA // 背景图像, 合成结果
+	content    *image.NRGBA // 恢复透明度的内容层
+	black      *image.NRGBA // 黑底读回的像素
+	white      *image.NRGBA // 白底读回的像素
+}
+
+func newLayer() *tLayer {
+	l := &tLayer{
+		onBlack: lcl.NewBitmap(),
+		onWhite: lcl.NewBitmap(),
+		result:  lcl.NewBitmap(),
+		img:     lcl.NewLazIntfImageWithIntX2RIQFlags(0, 0, types.NewSet(types.RiqfRGB, types.RiqfAlpha)),
+	}
+	l.onBlack.SetPixelFormat(types.Pf32bit)
+	l.onWhite.SetPixelFormat(types.Pf32bit)
+	l.result.SetPixelFormat(types.Pf32bit)
+	return l
+}
+
+// resize 调整离屏图像大小
+func (l *tLayer) resize(w, h int32) {
+	if l.background != nil && l.background.Rect.Dx() == int(w) && l.background.Rect.Dy() == int(h) {
+		return
+	}
+	rect := image.Rect(0, 0, int(w), int(h))
+	l.background = image.NewNRGBA(rect)
+	l.content = image.NewNRGBA(rect)
+	l.black = image.NewNRGBA(rect)
+	l.white = image.NewNRGBA(rect)
+	l.img.SetSize(w, h)
+	l.onBlack.SetSize(w, h)
+	l.onWhite.SetSize(w, h)
+	l.result.SetSize(w, h)
+}
+
+// drawContent 在黑色和白色底图上调用 draw, 恢复内容层到 l.content, 在主线程执行
+//
+//	font: 复制到离屏画布的字体
+func (l *tLayer) drawContent(font lcl.IFont, draw func(canvas lcl.ICanvas, rect types.TRect)) {
+	w, h := int32(l.content.Rect.Dx()), int32(l.content.Rect.Dy())
+	rect := types.TRect{Right: w, Bottom: h}
+	for _, layer := range []struct {
+		bitmap lcl.IBitmap
+		base   types.TColor
+		pixels *image.NRGBA
+	}{{l.onBlack, colors.ClBlack, l.black}, {l.onWhite, colors.ClWhite, l.white}} {
+		canvas := layer.bitmap.Canvas()
+		canvas.BrushToBrush().SetColor(layer.base)
+		canvas.FillRectWithRect(rect)
+		canvas.SetAntialiasingMode(types.AmOn)
+		canvas.FontToFont().Assign(font)
+		draw(canvas, rect)
+		img := layer.bitmap.CreateIntfImage()
+		readNRGBA(img, layer.pixels)
+		img.Free()
+	}
+	render.Unblend(l.content, l.black, l.white)
+}
+
+// present 把合成结果 l.background 画到画布
+func (l *tLayer) present(canvas lcl.ICanvas, rect types.TRect) {
+	loadNRGBA(l.img, l.background)
+	l.result.LoadFromIntfImage(l.img)
+	canvas.DrawWithIntX2Graphic(rect.Left, rect.Top, l.result)
+}
+
+func (l *tLayer) free() {
+	l.onBlack.Free()
+	l.onWhite.Free()
+	l.result.Free()
+	l.img.Free()
+}
+
+// SetOpacity 设置整体不透明度 0 ~ 255, 作用于背景, 文字和图标
+//
+//	与 SetAlpha 不同, SetAlpha 只影响背景渐变; 会停止正在进行的渐变动画
+func (m *TButton) SetOpacity(opacity byte) {
+	m.fadeSeq.Add(1)
+	m.setOpacity(opacity)
+}
+
+// Opacity 返回整体不透明度
+func (m *TButton) Opacity() byte {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.opacity
+}
+
+// setOpacity 设置整体不透明度并重绘, 不影响渐变动画
+func (m *TButton) setOpacity(opacity byte) {
+	m.lock.Lock()
+	changed := m.opacity != opacity
+	m.opacity = opacity
+	m.lock.Unlock()
+	if changed {
+		m.invalidate()
+	}
+}
+
+// FadeIn 显示按钮, 在 duration 内不透明度从 0 变到 255, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时也调用 done, 不透明度保持打断时的值
+func (m *TButton) FadeIn(duration time.Duration, done func()) {
+	seq := m.fadeSeq.Add(1)
+	m.setOpacity(0)
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.SetVisible(true)
+		}
+	})
+	animateOpacity(&m.fadeSeq, seq, 0, 255, duration, m.setOpacity, fadeDone(done))
+}
+
+// FadeOut 在 duration 内不透明度变到 0 后隐藏按钮, 结束后调用 done(可以为 nil)
+//
+//	隐藏后恢复开始时的不透明度, 之后直接 Show 时正常显示
+//	被新的渐变或 SetOpacity 打断时不隐藏, 也调用 done, 见 fadeOut
+func (m *TButton) FadeOut(duration time.Duration, done func()) {
+	fadeOut(&m.fadeSeq, duration, m.Opacity, m.setOpacity, func() {
+		if m.IsValid() {
+			m.SetVisible(false)
+		}
+	}, done)
+}
+
+// drawTranslucent 按整体不透明度绘制按钮, 在主线程执行
+func (m *TButton) drawTranslucent(canvas lcl.ICanvas, rect types.TRect, opacity byte) {
+	w, h := rect.Width(), rect.Height()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if m.layer == nil {
+		m.layer = newLayer()
+	}
+	l := m.layer
+	l.resize(w, h)
+	coverage, ok := m.paintBackground(l.background)
+	if !ok {
+		return
+	}
+	// 文字和图标绘制到内容层, 按形状裁剪后合成到背景上
+	l.drawContent(canvas.FontToFont(), m.drawContent)
+	render.Composite(l.background, l.content, coverage, opacity)
+	l.present(canvas, rect)
+}
+
+// paintBackground 在 Go 缓冲区中绘制当前状态的背景, 返回形状覆盖率
+//
+//	渐变背景的覆盖率来自圆角形状, 皮肤的覆盖率来自图片透明度
+func (m *TButton) paintBackground(dst *image.NRGBA) (coverage func(x, y int) float32, ok bool) {
+	w, h := dst.Rect.Dx(), dst.Rect.Dy()
+	m.lock.RLock()
+	skinImage, skin := m.skinImage()
+	var style render.Style
+	if skin == nil {
+		color := m.stateColor()
+		if color == nil {
+			m.lock.RUnlock()
+			return nil, false
+		}
+		style = color.Style(m.RoundedCorner, m.alpha, m.radius)
+	}
+	m.lock.RUnlock()
+	if skin != nil {
+		render.NineSlice(dst, skinImage, skin.Insets, skin.Mode)
+		return func(x, y int) float32 {
+			return float32(dst.Pix[dst.PixOffset(x, y)+3]) / 255
+		}, true
+	}
+	render.Paint(dst, style)
+	return func(x, y int) float32 {
+		return style.Coverage(x, y, w, h)
+	}, true
+}
+
+// fadeDone 把 FadeIn 和 FadeOut 的 done 转换为 animateOpacity 的 done, done 可以为 nil
+func fadeDone(done func()) func(cancelled bool) {
+	return func(cancelled bool) {
+		if done != nil {
+			done()
+		}
+	}
+}
+
+// fadeOut 在 duration 内把不透明度变到 0, 之后调用 hide 并恢复开始时的不透明度, 最后调用 done(可以为 nil)
+//
+//	被打断时不调用 hide; 不透明度仍是动画设置的值时恢复开始时的不透明度,
+//	已被 SetOpacity 或新的 FadeIn 修改时保留新的值, 之后调用 done
+func fadeOut(current *atomic.Uint64, duration time.Duration, opacity func() byte, set func(byte), hide func(), done func()) {
+	seq := current.Add(1)
+	from := opacity()
+	last := from // 动画最后设置的值, 只在主线程访问
+	animateOpacity(current, seq, from, 0, duration, func(value byte) {
+		last = value
+		set(value)
+	}, func(cancelled bool) {
+		if !cancelled {
+			hide()
+		}
+		if !cancelled || opacity() == last {
+			set(from)
+		}
+		if done != nil {
+			done()
+		}
+	})
+}
+
+// animateOpacity 在 duration 内把不透明度从 from 变到 to
+//
+//	每帧在主线程调用 set, 结束后在主线程调用 done(可以为 nil)
+//	seq 为开始动画时 current 的值, current 变化(新的动画或 SetOpacity)后停止, 也在主线程调用 done, cancelled 为 true
+func animateOpacity(current *atomic.Uint64, seq uint64, from, to byte, duration time.Duration, set func(byte), done func(cancelled bool)) {
+	start := time.Now()
+	ended := false // 是否已调用 done, 只在主线程访问
+	finish := func(cancelled bool) {
+		if !ended {
+			ended = true
+			if done != nil {
+				done(cancelled)
+			}
+		}
+	}
+	var step func()
+	step = func() {
+		if current.Load() != seq {
+			lcl.RunOnMainThreadAsync(func(id uint32) {
+				finish(true)
+			})
+			return
+		}
+		t := 1.0
+		if duration > 0 {
+			t = math.Min(1, float64(time.Since(start))/float64(duration))
+		}
+		value := byte(round(float64(from) + (float64(to)-float64(from))*t))
+		finished := t >= 1
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if current.Load() != seq {
+				finish(true)
+				return
+			}
+			set(value)
+			if finished {
+				finish(false)
+			}
+		})
+		if !finished {
+			time.AfterFunc(fadeFrameInterval, step)
+		}
+	}
+	step()
+}
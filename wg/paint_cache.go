@@ -0,0 +1,174 @@
+package wg
+
+import (
+	"container/list"
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/widget/render"
+	"image"
+	"sync"
+)
+
+// 默认保留的空闲(无引用)背景位图数量
+const defaultPaintCacheSize = 64
+
+// paintKey 背景位图缓存键, 样式, 大小和 DPI 相同的按钮共享同一张位图
+type paintKey struct {
+	style  render.Style
+	width  int32
+	height int32
+	dpi    int32
+}
+
+// paintEntry 缓存的背景位图
+type paintEntry struct {
+	key    paintKey
+	bitMap lcl.IBitmap
+	refs   int           // 引用计数
+	idle   *list.Element // 在空闲链表中的位置, 有引用时为 nil
+}
+
+// tPaintLRU 背景位图的引用计数和淘汰记录, 不创建也不释放位图
+//
+//	有引用的位图一直保留, 引用为 0 的位图进入空闲链表, 超出上限时按最近最少使用淘汰
+type tPaintLRU struct {
+	entries map[paintKey]*paintEntry
+	idle    *list.List // 空闲位图, 最近使用的在前
+	maxIdle int
+}
+
+func newPaintLRU(maxIdle int) *tPaintLRU {
+	return &tPaintLRU{
+		entries: make(map[paintKey]*paintEntry),
+		idle:    list.New(),
+		maxIdle: maxIdle,
+	}
+}
+
+// acquire 获取指定键的位图并增加引用, 不存在时调用 create 创建并加入缓存
+func (m *tPaintLRU) acquire(key paintKey, create func(key paintKey) lcl.IBitmap) *paintEntry {
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &paintEntry{key: key, bitMap: create(key)}
+		m.entries[key] = entry
+	}
+	if entry.idle != nil {
+		m.idle.Remove(entry.idle)
+		entry.idle = nil
+	}
+	entry.refs++
+	return entry
+}
+
+// release 减少引用, 引用为 0 时进入空闲链表, 返回被淘汰的位图
+func (m *tPaintLRU) release(entry *paintEntry) []lcl.IBitmap {
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+	entry.idle = m.idle.PushFront(entry)
+	return m.trim()
+}
+
+// setMaxIdle 设置保留的空闲位图数量, 返回被淘汰的位图
+func (m *tPaintLRU) setMaxIdle(size int) []lcl.IBitmap {
+	m.maxIdle = size
+	return m.trim()
+}
+
+// trim 淘汰超出上限的空闲位图, 返回被淘汰的位图
+func (m *tPaintLRU) trim() (evicted []lcl.IBitmap) {
+	for m.idle.Len() > 0 && m.idle.Len() > m.maxIdle {
+		entry := m.idle.Remove(m.idle.Back()).(*paintEntry)
+		entry.idle = nil
+		delete(m.entries, entry.key)
+		if entry.bitMap != nil {
+			evicted = append(evicted, entry.bitMap)
+		}
+	}
+	return
+}
+
+// tPaintCache 进程内共享的背景位图缓存
+type tPaintCache struct {
+	lock sync.Mutex
+	lru  *tPaintLRU
+	img  lcl.ILazIntfImage // 光栅化时共用的中间图像
+	buf  *image.NRGBA      // 光栅化时共用的缓冲区
+}
+
+var paintCache = &tPaintCache{
+	lru: newPaintLRU(defaultPaintCacheSize),
+}
+
+// SetPaintCacheSize 设置共享背景位图缓存保留的空闲位图数量
+//
+//	正在被按钮使用的位图不受限制, size <= 0 时不保留空闲位图
+func SetPaintCacheSize(size int) {
+	paintCache.lock.Lock()
+	evicted := paintCache.lru.setMaxIdle(size)
+	paintCache.lock.Unlock()
+	freeBitmaps(evicted)
+}
+
+// PaintCacheStats 返回缓存中的位图总数和其中空闲位图数量
+func PaintCacheStats() (total, idle int) {
+	paintCache.lock.Lock()
+	defer paintCache.lock.Unlock()
+	return len(paintCache.lru.entries), paintCache.lru.idle.Len()
+}
+
+// acquire 获取指定键的位图并增加引用, 不存在时绘制并加入缓存, 在主线程执行
+func (m *tPaintCache) acquire(key paintKey) *paintEntry {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.lru.acquire(key, m.paint)
+}
+
+// release 减少引用, 引用为 0 时进入空闲链表
+func (m *tPaintCache) release(entry *paintEntry) {
+	if entry == nil {
+		return
+	}
+	m.lock.Lock()
+	evicted := m.lru.release(entry)
+	m.lock.Unlock()
+	freeBitmaps(evicted)
+}
+
+// freeBitmaps 在主线程释放被淘汰的位图
+func freeBitmaps(bitMaps []lcl.IBitmap) {
+	if len(bitMaps) == 0 {
+		return
+	}
+	runOnMainThread(func() {
+		for _, bitMap := range bitMaps {
+			if bitMap.IsValid() {
+				bitMap.Free()
+			}
+		}
+	})
+}
+
+// paint 绘制新的背景位图
+func (m *tPaintCache) paint(key paintKey) lcl.IBitmap {
+	w, h := key.width, key.height
+	if m.img == nil {
+		m.img = lcl.NewLazIntfImageWithIntX2RIQFlags(0, 0, types.NewSet(types.RiqfRGB, types.RiqfAlpha))
+	}
+	if m.img.Width() != w || m.img.Height() != h {
+		m.img.SetSize(w, h)
+	}
+	if m.buf == nil || m.buf.Rect.Dx() != int(w) || m.buf.Rect.Dy() != int(h) {
+		m.buf = image.NewNRGBA(image.Rect(0, 0, int(w), int(h)))
+	}
+	// 在 Go 缓冲区中完成光栅化, 再整体写入中间图像
+	render.Paint(m.buf, key.style)
+	loadNRGBA(m.img, m.buf)
+	bitMap := lcl.NewBitmap()
+	bitMap.SetPixelFormat(types.Pf32bit)
+	bitMap.SetSize(w, h)
+	// 将处理好的图像数据加载到位图对象中
+	bitMap.LoadFromIntfImage(m.img)
+	return bitMap
+}
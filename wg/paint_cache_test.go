@@ -0,0 +1,82 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"testing"
+)
+
+// 簿记测试不创建位图, create 只记录调用次数
+func newTestLRU(maxIdle int) (*tPaintLRU, func(paintKey) lcl.IBitmap, *int) {
+	created := 0
+	create := func(paintKey) lcl.IBitmap {
+		created++
+		return nil
+	}
+	return newPaintLRU(maxIdle), create, &created
+}
+
+func testKey(width int32) paintKey {
+	return paintKey{width: width, height: 10, dpi: 96}
+}
+
+func TestPaintLRUShare(t *testing.T) {
+	lru, create, created := newTestLRU(4)
+	a := lru.acquire(testKey(1), create)
+	b := lru.acquire(testKey(1), create)
+	if a != b || *created != 1 || a.refs != 2 {
+		t.Fatalf("same key: entries %p %p, created %d, refs %d", a, b, *created, a.refs)
+	}
+	lru.acquire(testKey(2), create)
+	if *created != 2 || len(lru.entries) != 2 {
+		t.Fatalf("created %d, entries %d, want 2, 2", *created, len(lru.entries))
+	}
+}
+
+func TestPaintLRUIdle(t *testing.T) {
+	lru, create, created := newTestLRU(4)
+	a := lru.acquire(testKey(1), create)
+	lru.acquire(testKey(1), create)
+	lru.release(a)
+	if a.idle != nil || lru.idle.Len() != 0 {
+		t.Fatal("entry with references is idle")
+	}
+	lru.release(a)
+	if a.idle == nil || lru.idle.Len() != 1 {
+		t.Fatal("entry without references is not idle")
+	}
+	// 空闲的位图再次使用时不重新创建
+	if lru.acquire(testKey(1), create) != a || *created != 1 || lru.idle.Len() != 0 {
+		t.Fatal("idle entry not reused")
+	}
+}
+
+func TestPaintLRUEvict(t *testing.T) {
+	lru, create, _ := newTestLRU(2)
+	var entries []*paintEntry
+	for i := int32(1); i <= 3; i++ {
+		entries = append(entries, lru.acquire(testKey(i), create))
+	}
+	// 有引用的位图不受上限限制
+	lru.setMaxIdle(0)
+	if len(lru.entries) != 3 {
+		t.Fatalf("entries %d, want 3", len(lru.entries))
+	}
+	lru.setMaxIdle(2)
+	for _, e := range entries {
+		lru.release(e)
+	}
+	// 最早释放的最先淘汰
+	if len(lru.entries) != 2 || lru.entries[testKey(1)] != nil {
+		t.Fatalf("entries %v, want keys 2 and 3", lru.entries)
+	}
+	// 使用过的位图移到最近使用的位置
+	lru.release(lru.acquire(testKey(2), create))
+	lru.setMaxIdle(1)
+	if len(lru.entries) != 1 || lru.entries[testKey(2)] == nil {
+		t.Fatalf("entries %v, want key 2", lru.entries)
+	}
+	lru.setMaxIdle(0)
+	if len(lru.entries) != 0 || lru.idle.Len() != 0 {
+		t.Fatalf("entries %d, idle %d, want empty", len(lru.entries), lru.idle.Len())
+	}
+}
@@ -0,0 +1,106 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/widget/render"
+	"image"
+	"unsafe"
+)
+
+// pixelOrder 从 LCL 中间图像的原始图像描述读取像素的字节顺序
+//
+//	不是每分量 8 位的 32 位格式时返回 false, 只能逐像素读写
+func pixelOrder(img lcl.ILazIntfImage) (render.PixelOrder, bool) {
+	d := img.DataDescription()
+	return render.OrderOf(int(d.BitsPerPixel), d.ByteOrder == types.RiboMSBFirst,
+		render.Channel{Shift: int(d.RedShift), Prec: int(d.RedPrec)},
+		render.Channel{Shift: int(d.GreenShift), Prec: int(d.GreenPrec)},
+		render.Channel{Shift: int(d.BlueShift), Prec: int(d.BluePrec)},
+		render.Channel{Shift: int(d.AlphaShift), Prec: int(d.AlphaPrec)})
+}
+
+// dataLine 返回 img 第 y 行的原始像素数据, 长度为 width*4
+//
+//	GetDataLineStart 返回 LCL 分配的像素缓冲区地址, 不是 Go 内存, 不受 GC 移动和回收影响,
+//	在 img 释放或改变大小之前一直有效; 返回的切片只在 loadNRGBA, readNRGBA 调用期间使用
+func dataLine(img lcl.ILazIntfImage, y, width int) []byte {
+	line := img.GetDataLineStart(int32(y))
+	if line == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(line)), width*4)
+}
+
+// loadNRGBA 把 Go 缓冲区中的图像一次性写入 LCL 中间图像
+//
+//	按扫描线直接复制原始像素数据, 避免逐像素跨 purego 边界调用 SetColors
+//	原始数据不是 32 位格式时退回逐像素写入
+//	img 的大小必须与 src 相同
+func loadNRGBA(img lcl.ILazIntfImage, src *image.NRGBA) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	order, ok := pixelOrder(img)
+	if !ok {
+		setColors(img, src)
+		return
+	}
+	for y := 0; y < h; y++ {
+		dst := dataLine(img, y, w)
+		if dst == nil {
+			return
+		}
+		render.ConvertRow(dst, src.Pix[y*src.Stride:], w, order)
+	}
+}
+
+// readNRGBA 从 LCL 中间图像读取像素到 Go 缓冲区, loadNRGBA 的逆操作
+//
+//	img 的大小必须与 dst 相同
+func readNRGBA(img lcl.ILazIntfImage, dst *image.NRGBA) {
+	w, h := dst.Rect.Dx(), dst.Rect.Dy()
+	order, ok := pixelOrder(img)
+	if !ok {
+		getColors(img, dst)
+		return
+	}
+	for y := 0; y < h; y++ {
+		src := dataLine(img, y, w)
+		if src == nil {
+			return
+		}
+		render.ParseRow(dst.Pix[y*dst.Stride:], src, w, order)
+	}
+}
+
+// setColors 逐像素写入 LCL 中间图像, 适用于任意像素格式
+func setColors(img lcl.ILazIntfImage, src *image.NRGBA) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	for y := 0; y < h; y++ {
+		row := src.Pix[y*src.Stride:]
+		for x := 0; x < w; x++ {
+			i := x * 4
+			img.SetColors(int32(x), int32(y), lcl.TFPColor{
+				Red:   uint16(row[i+0]) << 8,
+				Green: uint16(row[i+1]) << 8,
+				Blue:  uint16(row[i+2]) << 8,
+				Alpha: uint16(row[i+3]) << 8,
+			})
+		}
+	}
+}
+
+// getColors 逐像素读取 LCL 中间图像, setColors 的逆操作
+func getColors(img lcl.ILazIntfImage, dst *image.NRGBA) {
+	w, h := dst.Rect.Dx(), dst.Rect.Dy()
+	for y := 0; y < h; y++ {
+		row := dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			c := img.Colors(int32(x), int32(y))
+			i := x * 4
+			row[i+0] = uint8(c.Red >> 8)
+			row[i+1] = uint8(c.Green >> 8)
+			row[i+2] = uint8(c.Blue >> 8)
+			row[i+3] = uint8(c.Alpha >> 8)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build lcl
+
+package wg
+
+import (
+	"fmt"
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/widget/render"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestLoadNRGBA(t *testing.T) {
+	// 中间图像只是内存中的数据, 不需要在主线程访问
+	src := render.Render(render.Style{Start: color.NRGBA{R: 0x10, G: 0x20, B: 0x30}, End: color.NRGBA{R: 0x40, G: 0x50, B: 0x60}, Alpha: 200}, 5, 3)
+	img := lcl.NewLazIntfImageWithIntX2RIQFlags(5, 3, types.NewSet(types.RiqfRGB, types.RiqfAlpha))
+	defer img.Free()
+	loadNRGBA(img, src)
+	// 按扫描线写入的结果与逐像素读取一致
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			c, want := img.Colors(int32(x), int32(y)), src.NRGBAAt(x, y)
+			if uint8(c.Red>>8) != want.R || uint8(c.Green>>8) != want.G || uint8(c.Blue>>8) != want.B || uint8(c.Alpha>>8) != want.A {
+				t.Errorf("pixel (%d, %d) = %v, want %v", x, y, c, want)
+			}
+		}
+	}
+	dst := image.NewNRGBA(src.Rect)
+	readNRGBA(img, dst)
+	for i := range src.Pix {
+		if dst.Pix[i] != src.Pix[i] {
+			t.Fatalf("readNRGBA differs at %d: %d, want %d", i, dst.Pix[i], src.Pix[i])
+		}
+	}
+}
+
+// 上传背景图像到 LCL 中间图像: 逐像素 SetColors 与按扫描线复制
+func BenchmarkLoadNRGBA(b *testing.B) {
+	for _, size := range []image.Point{{120, 40}, {800, 600}} {
+		src := render.Render(render.Style{Start: color.NRGBA{R: 255}, End: color.NRGBA{B: 255}, Alpha: 255}, size.X, size.Y)
+		img := lcl.NewLazIntfImageWithIntX2RIQFlags(int32(size.X), int32(size.Y), types.NewSet(types.RiqfRGB, types.RiqfAlpha))
+		name := fmt.Sprintf("%dx%d", size.X, size.Y)
+		b.Run(name+"/SetColors", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				setColors(img, src)
+			}
+		})
+		b.Run(name+"/scanline", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				loadNRGBA(img, src)
+			}
+		})
+		img.Free()
+	}
+}
@@ -0,0 +1,514 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"github.com/energye/lcl/types/keys"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TSegmented 分段控件
+//
+//	多个分段(TButton)排成一行, 共用一个圆角外框, 相邻分段共用边框, 只有两端外侧是圆角
+//	单选(默认)或多选, 每个分段可以设置图标, 获得焦点后方向键切换分段
+//
+//	方法可以在任意 goroutine 调用, 涉及 LCL 的操作会转到主线程执行.
+//	NewSegmented 和 AddSegment 创建 LCL 控件, 只能在主线程调用
+type TSegmented struct {
+	lcl.ICustomPanel
+	lock              sync.RWMutex     // 保护下列状态
+	segments          []*TButton       // 分段按钮
+	focusIndex        int              // 键盘焦点所在分段
+	multiSelect       bool             // 是否多选
+	radius            int32            // 外侧圆角
+	color             colors.TColor    // 未选中背景色
+	selectedColor     colors.TColor    // 选中背景色
+	textColor         colors.TColor    // 未选中文字颜色
+	selectedTextColor colors.TColor    // 选中文字颜色
+	borderColor       colors.TColor    // 边框颜色
+	onChange          lcl.TNotifyEvent // 用户点击或按键改变选中分段
+	// 整体不透明度, 作用于所有分段
+	opacity byte
+	fadeSeq atomic.Uint64
+}
+
+// NewSegmented 创建分段控件, 颜色取自当前调色板
+func NewSegmented(owner lcl.IComponent) *TSegmented {
+	p := Palette()
+	m := &TSegmented{
+		radius:            6,
+		color:             p.Background,
+		selectedColor:     p.Primary,
+		textColor:         p.Text,
+		selectedTextColor: p.TextOnFill,
+		borderColor:       p.Primary,
+		opacity:           255,
+	}
+	m.ICustomPanel = lcl.NewCustomPanel(owner)
+	m.SetBevelInner(types.BvNone)
+	m.SetBevelOuter(types.BvNone)
+	m.SetBorderStyleToBorderStyle(types.BsNone)
+	m.SetParentBackground(true)
+	m.SetParentColor(true)
+	m.SetTabStop(true)
+	m.SetHeight(28)
+	m.SetAccessibleRole(types.LarGroup)
+	m.SetOnResize(func(sender lcl.IObject) {
+		m.layout()
+	})
+	m.SetOnKeyDown(m.keyDown)
+	// 焦点变化时重绘焦点框
+	m.SetOnEnter(func(sender lcl.IObject) {
+		m.updateFocusRect()
+	})
+	m.SetOnExit(func(sender lcl.IObject) {
+		m.updateFocusRect()
+	})
+	return m
+}
+
+// AddSegment 添加分段, 返回分段按钮, 图标通过按钮的 SetIconFavorite 等方法设置
+//
+//	焦点框由 TSegmented 绘制, 分段按钮的 OnPaint 可以自由设置
+func (m *TSegmented) AddSegment(caption string) *TButton {
+	button := NewButton(m)
+	button.SetCaption(caption)
+	button.SetParent(m)
+	button.SetOnClick(func(sender lcl.IObject) {
+		index := m.indexOf(button)
+		if index < 0 {
+			return
+		}
+		m.lock.Lock()
+		m.focusIndex = index
+		m.lock.Unlock()
+		if m.CanFocus() {
+			m.SetFocus()
+		}
+		m.activate(index)
+		m.updateFocusRect()
+	})
+	m.lock.Lock()
+	m.segments = append(m.segments, button)
+	opacity := m.opacity
+	m.lock.Unlock()
+	button.setOpacity(opacity)
+	m.update()
+	return button
+}
+
+// RemoveSegment 删除分段
+func (m *TSegmented) RemoveSegment(index int) {
+	m.lock.Lock()
+	if index < 0 || index >= len(m.segments) {
+		m.lock.Unlock()
+		return
+	}
+	button := m.segments[index]
+	m.segments = append(m.segments[:index], m.segments[index+1:]...)
+	if m.focusIndex >= len(m.segments) {
+		m.focusIndex = len(m.segments) - 1
+	}
+	if m.focusIndex < 0 {
+		m.focusIndex = 0
+	}
+	m.lock.Unlock()
+	runOnMainThread(func() {
+		if button.IsValid() {
+			button.Free()
+		}
+	})
+	m.update()
+	m.updateFocusRect()
+}
+
+// Count 返回分段数量
+func (m *TSegmented) Count() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return len(m.segments)
+}
+
+// Segment 返回分段按钮, 序号无效时返回 nil
+func (m *TSegmented) Segment(index int) *TButton {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if index < 0 || index >= len(m.segments) {
+		return nil
+	}
+	return m.segments[index]
+}
+
+// SetMultiSelect 设置是否多选, 切换为单选时只保留第一个选中的分段
+func (m *TSegmented) SetMultiSelect(multiSelect bool) {
+	m.lock.Lock()
+	m.multiSelect = multiSelect
+	segments := m.segmentsLocked()
+	m.lock.Unlock()
+	if !multiSelect {
+		found := false
+		for _, button := range segments {
+			if button.Checked() {
+				if found {
+					button.SetChecked(false)
+				}
+				found = true
+			}
+		}
+	}
+	m.update()
+}
+
+// MultiSelect 返回是否多选
+func (m *TSegmented) MultiSelect() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.multiSelect
+}
+
+// SetSelected 设置分段是否选中, 单选时选中一个分段会取消其它分段, 不触发 OnChange
+func (m *TSegmented) SetSelected(index int, selected bool) {
+	m.lock.RLock()
+	multiSelect := m.multiSelect
+	segments := m.segmentsLocked()
+	m.lock.RUnlock()
+	if index < 0 || index >= len(segments) {
+		return
+	}
+	for i, button := range segments {
+		if i == index {
+			button.SetChecked(selected)
+		} else if selected && !multiSelect {
+			button.SetChecked(false)
+		}
+	}
+	m.update()
+}
+
+// Selected 返回分段是否选中
+func (m *TSegmented) Selected(index int) bool {
+	button := m.Segment(index)
+	return button != nil && button.Checked()
+}
+
+// SelectedIndex 返回第一个选中分段的序号, 没有选中时返回 -1
+func (m *TSegmented) SelectedIndex() int {
+	for i, button := range m.Segments() {
+		if button.Checked() {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectedIndexes 返回所有选中分段的序号
+func (m *TSegmented) SelectedIndexes() []int {
+	var result []int
+	for i, button := range m.Segments() {
+		if button.Checked() {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Segments 返回分段按钮列表的副本
+func (m *TSegmented) Segments() []*TButton {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.segmentsLocked()
+}
+
+// SetColors 设置未选中和选中的背景色
+func (m *TSegmented) SetColors(normal, selected colors.TColor) {
+	m.lock.Lock()
+	m.color, m.selectedColor = normal, selected
+	m.lock.Unlock()
+	m.update()
+}
+
+// SetTextColors 设置未选中和选中的文字颜色
+func (m *TSegmented) SetTextColors(normal, selected colors.TColor) {
+	m.lock.Lock()
+	m.textColor, m.selectedTextColor = normal, selected
+	m.lock.Unlock()
+	m.update()
+}
+
+// SetBorderColor 设置外框和分隔线颜色
+func (m *TSegmented) SetBorderColor(color colors.TColor) {
+	m.lock.Lock()
+	m.borderColor = color
+	m.lock.Unlock()
+	m.update()
+}
+
+// SetRadius 设置外侧圆角
+func (m *TSegmented) SetRadius(radius int32) {
+	m.lock.Lock()
+	m.radius = radius
+	m.lock.Unlock()
+	m.update()
+}
+
+// SetOnChange 选中分段被用户改变时触发, sender 为 TSegmented
+func (m *TSegmented) SetOnChange(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onChange = fn
+}
+
+// segmentsLocked 返回分段列表副本, 调用方持有锁
+func (m *TSegmented) segmentsLocked() []*TButton {
+	segments := make([]*TButton, len(m.segments))
+	copy(segments, m.segments)
+	return segments
+}
+
+// indexOf 返回分段按钮的序号, 不存在时返回 -1
+func (m *TSegmented) indexOf(button *TButton) int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for i, b := range m.segments {
+		if b == button {
+			return i
+		}
+	}
+	return -1
+}
+
+// activate 用户激活分段: 单选时选中, 多选时切换选中状态, 改变时触发 OnChange
+func (m *TSegmented) activate(index int) {
+	button := m.Segment(index)
+	if button == nil || button.Disable() {
+		return
+	}
+	if m.MultiSelect() {
+		m.SetSelected(index, !button.Checked())
+	} else if button.Checked() {
+		return
+	} else {
+		m.SetSelected(index, true)
+	}
+	m.lock.RLock()
+	fn := m.onChange
+	m.lock.RUnlock()
+	if fn != nil {
+		fn(m)
+	}
+}
+
+// keyDown 方向键移动焦点, 跳过禁用的分段, 单选时同时选中; 多选时空格和回车切换选中状态
+//
+//	菜单键和 Shift+F10 显示焦点分段的上下文菜单
+func (m *TSegmented) keyDown(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+	m.lock.RLock()
+	segments, focus, multiSelect := m.segmentsLocked(), m.focusIndex, m.multiSelect
+	m.lock.RUnlock()
+	count := len(segments)
+	if count == 0 {
+		return
+	}
+	enabled := make([]bool, count)
+	for i, button := range segments {
+		enabled[i] = !button.Disable()
+	}
+	var next int
+	switch *key {
+	case keys.VkLeft, keys.VkUp:
+		next = nextEnabled(enabled, focus, -1)
+	case keys.VkRight, keys.VkDown:
+		next = nextEnabled(enabled, focus, 1)
+	case keys.VkHome:
+		next = nextEnabled(enabled, -1, 1)
+	case keys.VkEnd:
+		next = nextEnabled(enabled, count, -1)
+	case keys.VkSpace, keys.VkReturn:
+		*key = 0
+		m.activate(focus)
+		return
+	case keys.VkApps, keys.VkF10:
+		if *key == keys.VkF10 && !shift.In(types.SsShift) {
+			return
+		}
+		*key = 0
+		if button := m.Segment(focus); button != nil {
+			button.ShowContextMenu()
+		}
+		return
+	default:
+		return
+	}
+	*key = 0
+	if next < 0 || next >= count || next == focus {
+		return
+	}
+	m.lock.Lock()
+	m.focusIndex = next
+	m.lock.Unlock()
+	if !multiSelect {
+		m.activate(next)
+	}
+	m.updateFocusRect()
+}
+
+// nextEnabled 返回从 from 开始(不含)按 step 方向的第一个启用的分段, 没有时返回 -1
+func nextEnabled(enabled []bool, from, step int) int {
+	for i := from + step; i >= 0 && i < len(enabled); i += step {
+		if enabled[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// update 更新分段的圆角, 边框, 颜色和位置
+func (m *TSegmented) update() {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		m.applyStyle()
+		m.layout()
+	})
+}
+
+// applyStyle 应用分段样式, 在主线程执行
+//
+//	每个分段绘制左, 上, 下边框, 最后一个再绘制右边框, 相邻分段的分隔线只有一条
+func (m *TSegmented) applyStyle() {
+	m.lock.RLock()
+	segments := m.segmentsLocked()
+	multiSelect, radius := m.multiSelect, m.radius
+	color, selectedColor := m.color, m.selectedColor
+	textColor, selectedTextColor := m.textColor, m.selectedTextColor
+	borderColor := m.borderColor
+	m.lock.RUnlock()
+	role := ArRadioButton
+	if multiSelect {
+		role = ArToggleButton
+	}
+	last := len(segments) - 1
+	for i, button := range segments {
+		corners := types.NewSet()
+		if i == 0 {
+			corners = corners.Include(RcLeftTop, RcLeftBottom)
+		}
+		if i == last {
+			corners = corners.Include(RcRightTop, RcRightBottom)
+		}
+		button.SetRoundedCorners(corners)
+		button.SetRadius(radius)
+		directions := types.NewSet(BbdLeft, BbdTop, BbdBottom)
+		if i == last {
+			directions = directions.Include(BbdRight)
+		}
+		button.SetBorderDirections(directions)
+		button.SetBorderWidth(BbdNone, 1)
+		button.SetBorderColor(BbdNone, borderColor)
+		// 选中时默认状态使用按下颜色, 按下颜色与选中颜色相同
+		if button.Checked() {
+			button.SetDefaultColor(selectedColor, selectedColor)
+			button.SetEnterColor(DarkenColor(selectedColor, 0.1), DarkenColor(selectedColor, 0.1))
+			button.SetDownColor(selectedColor, selectedColor)
+			button.Font().SetColor(selectedTextColor)
+		} else {
+			enter, down := shadeColor(color, 0.08), shadeColor(color, 0.16)
+			button.SetDefaultColor(color, color)
+			button.SetEnterColor(enter, enter)
+			button.SetDownColor(down, down)
+			button.Font().SetColor(textColor)
+		}
+		button.setAccessibleRole(role)
+		button.Invalidate()
+	}
+}
+
+// layout 分段平分控件宽度, 余数给最后一个分段, 在主线程执行
+func (m *TSegmented) layout() {
+	segments := m.Segments()
+	count := int32(len(segments))
+	if count == 0 {
+		return
+	}
+	width, height := m.ClientWidth(), m.ClientHeight()
+	segmentWidth := width / count
+	left := int32(0)
+	for i, button := range segments {
+		w := segmentWidth
+		if int32(i) == count-1 {
+			w = width - left
+		}
+		button.SetBounds(left, 0, w, height)
+		left += w
+	}
+}
+
+// updateFocusRect 控件获得焦点时在焦点分段上绘制焦点框
+func (m *TSegmented) updateFocusRect() {
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		focused := m.Focused()
+		m.lock.RLock()
+		segments, focus := m.segmentsLocked(), m.focusIndex
+		m.lock.RUnlock()
+		for i, button := range segments {
+			if button.IsValid() {
+				button.setFocusRect(focused && i == focus)
+			}
+		}
+	})
+}
+
+// SetOpacity 设置所有分段的整体不透明度 0 ~ 255, 会停止正在进行的渐变动画
+func (m *TSegmented) SetOpacity(opacity byte) {
+	m.fadeSeq.Add(1)
+	m.setOpacity(opacity)
+}
+
+// Opacity 返回整体不透明度
+func (m *TSegmented) Opacity() byte {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.opacity
+}
+
+// setOpacity 设置所有分段的不透明度, 不影响渐变动画
+func (m *TSegmented) setOpacity(opacity byte) {
+	m.lock.Lock()
+	m.opacity = opacity
+	m.lock.Unlock()
+	for _, button := range m.Segments() {
+		button.setOpacity(opacity)
+	}
+}
+
+// FadeIn 显示控件, 在 duration 内不透明度从 0 变到 255, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时也调用 done, 不透明度保持打断时的值
+func (m *TSegmented) FadeIn(duration time.Duration, done func()) {
+	seq := m.fadeSeq.Add(1)
+	m.setOpacity(0)
+	runOnMainThread(func() {
+		if m.IsValid() {
+			m.SetVisible(true)
+		}
+	})
+	animateOpacity(&m.fadeSeq, seq, 0, 255, duration, m.setOpacity, fadeDone(done))
+}
+
+// FadeOut 在 duration 内不透明度变到 0 后隐藏控件并恢复开始时的不透明度, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时不隐藏, 也调用 done, 见 fadeOut
+func (m *TSegmented) FadeOut(duration time.Duration, done func()) {
+	fadeOut(&m.fadeSeq, duration, m.Opacity, m.setOpacity, func() {
+		if m.IsValid() {
+			m.SetVisible(false)
+		}
+	}, done)
+}
@@ -0,0 +1,29 @@
+package wg
+
+import "testing"
+
+func TestNextEnabled(t *testing.T) {
+	enabled := []bool{false, true, false, true, true, false}
+	tests := []struct {
+		name       string
+		from, step int
+		want       int
+	}{
+		{"right skips disabled", 1, 1, 3},
+		{"right to neighbour", 3, 1, 4},
+		{"right at last enabled", 4, 1, -1},
+		{"left skips disabled", 3, -1, 1},
+		{"left at first enabled", 1, -1, -1},
+		{"home", -1, 1, 1},
+		{"end", len(enabled), -1, 4},
+		{"from disabled focus", 2, 1, 3},
+	}
+	for _, tt := range tests {
+		if got := nextEnabled(enabled, tt.from, tt.step); got != tt.want {
+			t.Errorf("%s: nextEnabled(%d, %d) = %d, want %d", tt.name, tt.from, tt.step, got, tt.want)
+		}
+	}
+	if got := nextEnabled([]bool{false, false}, -1, 1); got != -1 {
+		t.Errorf("all disabled = %d, want -1", got)
+	}
+}
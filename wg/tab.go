@@ -4,8 +4,9 @@ import (
 	"github.com/energye/lcl/lcl"
 	"github.com/energye/lcl/types"
 	"github.com/energye/lcl/types/colors"
-	"github.com/energye/widget/assets"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,22 +21,96 @@ var (
 	scrollStep      = int32(15)
 )
 
+// TTabHints 页签栏按钮的提示, 也是按钮的可访问名称, 可以设置为界面语言
+type TTabHints struct {
+	ScrollLeft  string // 横向页签栏的滚动导航按钮
+	ScrollRight string //
+	ScrollUp    string // 纵向页签栏的滚动导航按钮
+	ScrollDown  string //
+	AllTabs     string // 页签列表按钮
+}
+
+// DefaultTabHints 新建 TTab 使用的提示
+var DefaultTabHints = TTabHints{
+	ScrollLeft:  "Scroll left",
+	ScrollRight: "Scroll right",
+	ScrollUp:    "Scroll up",
+	ScrollDown:  "Scroll down",
+	AllTabs:     "All tabs",
+}
+
 type TTab struct {
 	lcl.ICustomPanel                   //
+	lock              sync.RWMutex     // 保护下列状态
 	pages             []*TPage         // 页列表
-	totalTabWidth     int32            // 页签总宽度
+	contentWidth      int32            // 页签总宽度, 不包括滚动偏移和导航按钮
 	deleting          bool             // 正在删除中 page
 	scrollLeftBtn     *TButton         // tab 滚动导航按钮 左滚动
 	scrollRightBtn    *TButton         // tab 滚动导航按钮 右滚动
 	scrollOffset      int32            // tab 滚动导航按钮 偏移坐标
 	scrollTimer       *time.Timer      // tab 滚动连续
 	triggerScrollStop bool             // 触发滚动是否停止
+	hints             TTabHints        // 滚动导航按钮和页签列表按钮的提示
 	onChange          lcl.TNotifyEvent //
-	Margin            int32            // 两个 tab 之间的距离
+	Margin            int32            // 两个 tab 之间的距离, 只能在主线程直接修改, 其它 goroutine 使用 SetMargin
+	// 页签上下文菜单, 应用到所有页签
+	tabPopupMenu     lcl.IPopupMenu
+	onTabContextMenu TContextMenuEvent
+	// 拖动排序
+	onReorder  TReorderEvent
+	fixedOrder bool
+	drag       *tTabDrag          // 只在主线程访问
+	slides     map[*TButton]int32 // 滑动中的按钮和目标位置, 只在主线程访问
+	sliding    bool               // 只在主线程访问
+	// 拖出页签
+	tearOff     bool
+	acceptDrop  bool
+	window      lcl.IForm // 拖出页签时创建的窗口
+	onTearOff   TTearOffEvent
+	onTabWindow TTabWindowEvent
+	onPageDrop  TPageDropEvent
+	onDestroy   func() // 用户的销毁事件, 在内部清理之后调用
+	// 页签栏按钮的整体不透明度
+	opacity byte
+	fadeSeq atomic.Uint64
+	// 页签栏位置, 只在主线程修改
+	position    TTabPosition
+	rotatedText bool
+	stripWidth  int32 // 纵向横排文字时的宽度
+	// 多行
+	multiRow          bool // 只在主线程修改
+	activeRowAdjacent bool
+	rows              int
+	// 滚动
+	scrollBtnMode    TScrollButtonMode
+	scrollBtnHidden  bool   // EnableScrollButton(false)
+	noSmoothScroll   bool   //
+	noScrollToActive bool   //
+	scrollTarget     int32  // 平滑滚动的目标偏移, 只在主线程访问
+	scrolling        bool   // 只在主线程访问
+	scrollPage       *TPage // 等待滚动到可见位置的页, 只在主线程访问
+	// 页签列表
+	overflowBtn        *TButton
+	overflowShowHidden bool
+	overflowForm       lcl.IForm // 打开的页签列表, 只在主线程访问
+	overflowClosed     time.Time // 页签列表关闭时间, 只在主线程访问
+	// 键盘
+	shortcuts map[TTabCommand][]TTabShortcut
+	mruOrder  bool
+	switcher  bool
+	mru       []*TPage   // 最近激活的页, 只在主线程访问
+	cycle     *tTabCycle // 只在主线程访问
+	// 固定页签
+	pinnedWidth int32 // 固定页签占用的长度, 只在主线程访问
+	onPinChange TPinEvent
+	// 页签组
+	groups      []*TTabGroup
+	onPageGroup TPageGroupEvent
 }
 
 type TPage struct {
 	lcl.ICustomPanel
+	lock         sync.RWMutex // 保护下列状态
 	tabSheet     lcl.ICustomPage
 	active       bool     // 是否激活
 	show         bool     // 是否显示
@@ -47,8 +122,22 @@ type TPage struct {
 	onClick      lcl.TNotifyEvent
 	activeColor  types.TColor //
 	defaultColor types.TColor //
-}
-
+	// 页签上下文菜单, 为空时使用 TTab 的设置
+	tabPopupMenu     lcl.IPopupMenu
+	onTabContextMenu TContextMenuEvent
+	// 锁定位置, 不能拖动
+	lockedPosition bool
+	// 固定页签
+	pinned         bool
+	unpinnedLength int32 // 固定前沿文字方向的长度
+	// 所在的页签组
+	group *TTabGroup
+}
+
+// NewTab 创建 Tab
+//
+//	TTab 和 TPage 的方法可以在任意 goroutine 调用, 涉及 LCL 的操作会转到主线程执行.
+//	NewTab 和 NewPage 创建 LCL 控件, 只能在主线程调用
 func NewTab(owner lcl.IComponent) *TTab {
 	tab := &TTab{}
 	tab.ICustomPanel = lcl.NewCustomPanel(owner)
@@ -56,7 +145,16 @@ func NewTab(owner lcl.IComponent) *TTab {
 	tab.SetBevelOuter(types.BvNone)
 	//tab.SetColor(colors.ClRed)
 	tab.SetBorderStyleToBorderStyle(types.BsNone)
+	// 可访问: 页签列表, 页签按钮角色为 ArTab
+	tab.SetAccessibleRole(types.LarTabControl)
+	tab.stripWidth = verticalStripWidth
+	tab.hints = DefaultTabHints
+	tab.opacity = 255
 	tab.initScrollBtn()
+	tab.initOverflowBtn()
+	tab.applyHints()
+	tab.initKeys()
+	registerDropTab(tab)
 	return tab
 }
 
@@ -65,7 +163,7 @@ func (m *TTab) initScrollBtn() {
 	m.scrollLeftBtn = NewButton(m)
 	m.scrollRightBtn = NewButton(m)
 
-	m.scrollLeftBtn.SetIconFormBytes(assets.Tab("scroll-left.png"))
+	m.scrollLeftBtn.SetIconAsset("tab/scroll-left")
 	m.scrollLeftBtn.SetWidth(scrollBtnWidth)
 	m.scrollLeftBtn.SetHeight(scrollBtnHeight)
 	m.scrollLeftBtn.SetLeft(2)
@@ -75,7 +173,7 @@ func (m *TTab) initScrollBtn() {
 	m.scrollLeftBtn.SetColor(LightenColor(colors.ClGray, 0.2))
 	m.scrollLeftBtn.SetParent(m)
 
-	m.scrollRightBtn.SetIconFormBytes(assets.Tab("scroll-right.png"))
+	m.scrollRightBtn.SetIconAsset("tab/scroll-right")
 	m.scrollRightBtn.SetWidth(scrollBtnWidth)
 	m.scrollRightBtn.SetHeight(scrollBtnHeight)
 	//m.scrollRightBtn.SetTop(2)
@@ -85,18 +183,27 @@ func (m *TTab) initScrollBtn() {
 	m.scrollRightBtn.SetParent(m)
 
 	scrollBtnMouseUp := func(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
-		m.triggerScrollStop = true
+		m.setTriggerScrollStop(true)
 	}
 	m.scrollLeftBtn.SetOnMouseDown(func(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
-		m.triggerScrollStop = false
+		if button != types.MbLeft {
+			return
+		}
+		m.setTriggerScrollStop(false)
 		m.triggerScrollLoop(time.Second/2, 1)
 	})
 	m.scrollLeftBtn.SetOnMouseUp(scrollBtnMouseUp)
 	m.scrollRightBtn.SetOnMouseDown(func(sender lcl.IObject, button types.TMouseButton, shift types.TShiftState, X int32, Y int32) {
-		m.triggerScrollStop = false
+		if button != types.MbLeft {
+			return
+		}
+		m.setTriggerScrollStop(false)
 		m.triggerScrollLoop(time.Second/2, 2)
 	})
 	m.scrollRightBtn.SetOnMouseUp(scrollBtnMouseUp)
+	m.initStripWheel(m, true)
+	m.initStripWheel(m.scrollLeftBtn, false)
+	m.initStripWheel(m.scrollRightBtn, false)
 }
 
 func (m *TTab) NewPage() *TPage {
@@ -107,10 +214,11 @@ func (m *TTab) NewPage() *TPage {
 	button := NewButton(m)
 	//button.SetAutoSize(true)
 	//button.SetShowHint(true)
+	m.lock.RLock()
 	button.SetCaption(defaultPrefix + strconv.Itoa(len(m.pages)))
+	m.lock.RUnlock()
 	button.Font().SetSize(9)
 	button.Font().SetColor(colors.Cl3DFace)
-	button.RoundedCorner = button.RoundedCorner.Exclude(RcLeftBottom).Exclude(RcRightBottom)
 	button.SetRadius(0)
 	button.SetAlpha(255)
 	button.SetHeight(defaultHeight)
@@ -118,51 +226,80 @@ func (m *TTab) NewPage() *TPage {
 	button.SetEnterColor(DarkenColor(defaultColor, 0.1), DarkenColor(defaultColor, 0.1))
 	button.SetDownColor(DarkenColor(defaultColor, 0.2), DarkenColor(defaultColor, 0.2))
 	button.SetBorderColor(BbdNone, DarkenColor(defaultColor, 0.3))
+	button.setAccessibleRole(ArTab)
+	button.setOpacity(m.Opacity())
+	button.tracker = m
+	m.applyButtonPosition(button)
 	button.SetParent(m)
+	m.initStripWheel(button, false)
 	page.button = button
 
-	tabRect := m.ClientRect()
 	sheet := lcl.NewCustomPanel(m)
 	sheet.SetBevelInner(types.BvNone)
 	sheet.SetBevelOuter(types.BvNone)
 	sheet.SetBorderStyleToBorderStyle(types.BsNone)
-	sheet.SetTop(button.Height())
-	sheet.SetHeight(tabRect.Height() - button.Height())
-	sheet.SetWidth(tabRect.Width())
 	sheet.SetAlign(types.AlCustom)
 	sheet.SetAnchors(types.NewSet(types.AkLeft, types.AkTop, types.AkRight, types.AkBottom))
+	sheet.SetAccessibleRole(types.LarGroup)
 	sheet.SetParent(m)
 	page.ICustomPanel = sheet
+	m.layoutSheet(page)
+	page.updateAccessible()
 
 	tabSheet := lcl.NewCustomPage(m)
 	tabSheet.SetParent(sheet)
 	page.tabSheet = tabSheet
 
+	m.lock.Lock()
 	m.pages = append(m.pages, page) // 添加到页列表
-	page.initEvent()                // 初始化事件
+	m.lock.Unlock()
+	page.initEvent() // 初始化事件
+	page.applyTabPopupMenu()
 	page.SetActive(false)
 
 	// 事件处理
 	tabSheet.SetOnShow(func(sender lcl.IObject) {
-		if m.onChange != nil {
-			m.onChange(page)
+		// 页可能已移到其它 TTab, 使用当前所属的 TTab
+		if tab := page.tab; tab != nil {
+			tab.lock.RLock()
+			onChange := tab.onChange
+			tab.lock.RUnlock()
+			if onChange != nil {
+				onChange(page)
+			}
 		}
-		if page.onShow != nil {
-			page.onShow(page)
+		page.lock.RLock()
+		onShow := page.onShow
+		page.lock.RUnlock()
+		if onShow != nil {
+			onShow(page)
 		}
 	})
 	tabSheet.SetOnHide(func(sender lcl.IObject) {
-		if page.onHide != nil {
-			page.onHide(sender)
+		page.lock.RLock()
+		onHide := page.onHide
+		page.lock.RUnlock()
+		if onHide != nil {
+			onHide(sender)
 		}
 	})
 	return page
 }
 
 func (m *TTab) SetOnChange(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onChange = fn
 }
 
+// SetMargin 设置两个页签之间的距离并重新排列
+func (m *TTab) SetMargin(margin int32) {
+	m.lock.Lock()
+	m.Margin = margin
+	m.lock.Unlock()
+	m.RecalculatePosition()
+}
+
 func (m *TTab) ScrollLeft() *TButton {
 	return m.scrollLeftBtn
 }
@@ -171,16 +308,24 @@ func (m *TTab) ScrollRight() *TButton {
 	return m.scrollRightBtn
 }
 
+// EnableScrollButton 设置是否显示滚动导航按钮, SbmAutoHide 方式时没有溢出也会隐藏
 func (m *TTab) EnableScrollButton(value bool) {
-	if m.scrollLeftBtn != nil {
-		m.scrollLeftBtn.SetVisible(value)
-	}
-	if m.scrollRightBtn != nil {
-		m.scrollRightBtn.SetVisible(value)
-	}
+	m.lock.Lock()
+	m.scrollBtnHidden = !value
+	m.lock.Unlock()
+	m.RecalculatePosition()
+}
+
+// setTriggerScrollStop 设置触发滚动是否停止
+func (m *TTab) setTriggerScrollStop(stop bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.triggerScrollStop = stop
 }
 
 func (m *TTab) triggerScrollLoop(afterTime time.Duration, scrollLeftOrRight int32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	if m.triggerScrollStop {
 		if m.scrollTimer != nil {
 			m.scrollTimer.Stop()
@@ -200,50 +345,85 @@ func (m *TTab) triggerScrollLoop(afterTime time.Duration, scrollLeftOrRight int3
 	})
 }
 
-// 向左滚动
+// 向左滚动, 显示左侧页签
 func (m *TTab) scrollLeft() {
-	scrollLeft := int32(0)
-	if m.scrollLeftBtn.Visible() {
-		scrollLeft = scrollBtnWidth + scrollBtnMargin
-	}
-	if m.scrollOffset+scrollLeft < scrollLeft {
-		m.scrollOffset += scrollStep
-		m.RecalculatePosition()
-	} else {
-		m.triggerScrollStop = true
+	if !m.scrollBy(scrollStep) {
+		m.setTriggerScrollStop(true)
 	}
 }
 
-// 向右滚动
+// 向右滚动, 显示右侧页签
 func (m *TTab) scrollRight() {
-	width := m.Width()
-	widths := m.totalTabWidth + scrollBtnWidth + scrollBtnMargin
-	if widths > width {
-		m.scrollOffset += -scrollStep
-		m.RecalculatePosition()
-	} else {
-		m.triggerScrollStop = true
+	if !m.scrollBy(-scrollStep) {
+		m.setTriggerScrollStop(true)
 	}
 }
 
 // RecalculatePosition 重新计算位置, 在隐藏/移除时使用
 func (m *TTab) RecalculatePosition() {
-	widths := m.scrollOffset + m.Margin
-	if m.scrollLeftBtn != nil && m.scrollLeftBtn.Visible() {
-		widths += scrollBtnWidth + scrollBtnMargin
+	runOnMainThread(m.recalculatePosition)
+}
+
+// recalculatePosition 重新计算位置, 在主线程执行
+func (m *TTab) recalculatePosition() {
+	if !m.IsValid() {
+		return
 	}
-	for _, page := range m.pages {
-		if page.button.Visible() {
-			page.button.AutoSizeWidth()
-			br := page.button.BoundsRect()
-			width := br.Width()
-			br.Left = widths
-			br.SetWidth(width)
-			page.button.SetBoundsRect(br)
-			widths += br.Width() + m.Margin
+	m.lock.RLock()
+	margin := m.Margin
+	pages := m.pages
+	m.lock.RUnlock()
+	items := m.stripItems(pages)
+	// 固定的页签不滚动, 不计入滚动范围
+	content, pinned := margin, int32(0)
+	for _, item := range items {
+		item.button.AutoSizeWidth()
+		if item.pinned() {
+			pinned += m.btnLen(item.button) + margin
+		} else {
+			content += m.btnLen(item.button) + margin
 		}
 	}
-	m.totalTabWidth = widths
+	if pinned > 0 && !m.multiRow {
+		m.pinnedWidth = pinned + margin
+	} else {
+		m.pinnedWidth = 0
+	}
+	m.lock.Lock()
+	m.contentWidth = content
+	m.lock.Unlock()
+	// 页签宽度变化后滚动导航按钮可能显示或隐藏, 滚动偏移限制在新的范围内
+	m.updateScrollBtnVisible()
+	if m.multiRow {
+		m.layoutRows(pages, items, margin)
+		m.scrollBtnPosition()
+		return
+	}
+	m.setRows(1, pages)
+	minOffset := m.minScrollOffset()
+	offset := m.clampScroll(m.ScrollOffset())
+	m.scrollTarget = m.clampScroll(m.scrollTarget)
+	m.lock.Lock()
+	m.scrollOffset = offset
+	m.lock.Unlock()
+	widths := offset + margin + m.stripStart()
+	pinnedPos := margin
+	for _, item := range items {
+		button := item.button
+		m.placeCross(button)
+		if item.pinned() {
+			m.placeButton(button, pinnedPos)
+			pinnedPos += m.btnLen(button) + margin
+			// 滚动后其它页签移到固定页签下方
+			if offset < 0 {
+				button.BringToFront()
+			}
+			continue
+		}
+		m.placeButton(button, widths)
+		widths += m.btnLen(button) + margin
+	}
+	m.updateScrollBtnEnabled(offset, minOffset)
 	// 滚动导航按钮 位置调整
 	m.scrollBtnPosition()
 }
@@ -251,19 +431,26 @@ func (m *TTab) RecalculatePosition() {
 // 滚动导航按钮 位置调整
 func (m *TTab) scrollBtnPosition() {
 	if m.scrollLeftBtn != nil && m.scrollLeftBtn.Visible() {
-		m.scrollLeftBtn.SetLeft(2)
+		m.placeCross(m.scrollLeftBtn)
+		m.setBtnPos(m.scrollLeftBtn, m.pinnedWidth+2)
 		m.scrollLeftBtn.BringToFront()
 	}
 	if m.scrollRightBtn != nil && m.scrollRightBtn.Visible() {
-		m.scrollRightBtn.SetLeft(m.Width() - scrollBtnWidth - 2)
+		m.placeCross(m.scrollRightBtn)
+		m.setBtnPos(m.scrollRightBtn, m.stripLength()-scrollBtnWidth-2-m.overflowWidth())
 		m.scrollRightBtn.BringToFront()
 	}
+	if m.overflowBtn != nil && m.overflowBtn.Visible() {
+		m.placeCross(m.overflowBtn)
+		m.setBtnPos(m.overflowBtn, m.stripLength()-scrollBtnWidth-2)
+		m.overflowBtn.BringToFront()
+	}
 }
 
 // HideAllActivated 隐藏所有激活页面
 func (m *TTab) HideAllActivated() {
-	for _, page := range m.pages {
-		if page.active {
+	for _, page := range m.Pages() {
+		if page.Active() {
 			page.SetActive(false)
 		}
 	}
@@ -271,11 +458,24 @@ func (m *TTab) HideAllActivated() {
 
 // 删除指定 page
 func (m *TTab) RemovePage(removePage *TPage) {
+	runOnMainThread(func() {
+		m.removePage(removePage)
+	})
+}
+
+// removePage 删除指定 page, 在主线程执行
+func (m *TTab) removePage(removePage *TPage) {
 	removeIndex := -1 // 存放当前删除page的索引
 	var (
 		newPages []*TPage // 替换为实际元素类型
 		found    bool
 	)
+	if m.drag != nil && m.drag.page == removePage {
+		m.drag = nil
+	}
+	m.removeMRU(removePage)
+	m.clearPageGroup(removePage)
+	m.lock.Lock()
 	for i, page := range m.pages {
 		if page == removePage {
 			removeIndex = i
@@ -287,15 +487,17 @@ func (m *TTab) RemovePage(removePage *TPage) {
 			break
 		}
 	}
+	pages := m.pages
+	m.lock.Unlock()
 	// 重新计算 button 位置
-	m.RecalculatePosition()
-	if removePage.active && found {
+	m.recalculatePosition()
+	if removePage.Active() && found {
 		// 根据删除索引获取要显示的 page
 		var showPage *TPage
-		if removeIndex != -1 && removeIndex < len(m.pages) {
-			showPage = m.pages[removeIndex] // 显示当前索引的 page, 也就是删除后的下一个
-		} else if len(m.pages) > 0 {
-			showPage = m.pages[0] // 显示第一个 page
+		if removeIndex != -1 && removeIndex < len(pages) {
+			showPage = pages[removeIndex] // 显示当前索引的 page, 也就是删除后的下一个
+		} else if len(pages) > 0 {
+			showPage = pages[0] // 显示第一个 page
 		}
 		if showPage != nil {
 			lcl.RunOnMainThreadAsync(func(id uint32) {
@@ -305,11 +507,27 @@ func (m *TTab) RemovePage(removePage *TPage) {
 			})
 		}
 	}
+	m.lock.Lock()
 	m.deleting = false
+	window := m.window
+	m.lock.Unlock()
+	// 拖出窗口中没有页时关闭窗口
+	if window != nil && len(pages) == 0 {
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if window.IsValid() {
+				window.Close()
+			}
+		})
+	}
 }
 
+// Pages 返回页列表的副本
 func (m *TTab) Pages() []*TPage {
-	return m.pages
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	pages := make([]*TPage, len(m.pages))
+	copy(pages, m.pages)
+	return pages
 }
 
 func (m *TPage) Free() {
@@ -320,6 +538,11 @@ func (m *TPage) Free() {
 
 // 删除掉自己
 func (m *TPage) Remove() {
+	runOnMainThread(m.remove)
+}
+
+// remove 删除掉自己, 在主线程执行
+func (m *TPage) remove() {
 	m.button.SetOnClick(nil)
 	m.button.SetOnCloseClick(nil)
 	// 先隐藏掉
@@ -327,7 +550,7 @@ func (m *TPage) Remove() {
 	m.ICustomPanel.Hide()
 	m.tabSheet.Hide()
 	// 在page里删除自己
-	m.tab.RemovePage(m)
+	m.tab.removePage(m)
 	// 最后释放掉
 	m.Free()
 	m.tab = nil
@@ -336,93 +559,152 @@ func (m *TPage) Remove() {
 func (m *TPage) SetCaption(name string) {
 	m.button.SetCaption(name)
 	lcl.RunOnMainThreadAsync(func(id uint32) {
-		m.tab.RecalculatePosition()
+		m.updateAccessible()
+		if m.tab != nil {
+			m.tab.RecalculatePosition()
+		}
 	})
 }
 
 func (m *TPage) SetActiveColor(color types.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.activeColor = color
 }
 func (m *TPage) SetDefaultColor(color types.TColor) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.defaultColor = color
 }
 
 func (m *TPage) Active() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	return m.active
 }
 
 // 激活自己, 会取消其它激活的
 func (m *TPage) SetActive(active bool) {
+	m.lock.Lock()
 	m.active = active
+	color := m.defaultColor
 	if active {
-		m.button.SetDefaultColor(m.activeColor, m.activeColor)
-		m.ICustomPanel.Show()
-		m.tabSheet.Show()
-	} else {
-		m.button.SetDefaultColor(m.defaultColor, m.defaultColor)
-		m.ICustomPanel.Hide()
-		m.tabSheet.Hide()
+		color = m.activeColor
 	}
-	m.button.Invalidate()
+	m.lock.Unlock()
+	m.button.SetDefaultColor(color, color)
+	m.button.setSelected(active)
+	runOnMainThread(func() {
+		if !m.IsValid() {
+			return
+		}
+		if active {
+			m.ICustomPanel.Show()
+			m.tabSheet.Show()
+			if m.tab != nil {
+				m.tab.pageActivated(m)
+				m.tab.scrollToActiveLater(m)
+			}
+		} else {
+			m.ICustomPanel.Hide()
+			m.tabSheet.Hide()
+		}
+		m.button.Invalidate()
+	})
 }
 
 // 隐藏自己, button 和 page 同时隐藏
 func (m *TPage) Hide() {
-	m.button.Hide()
-	m.SetActive(false)
-	m.tab.RecalculatePosition()
+	runOnMainThread(func() {
+		m.button.Hide()
+		m.SetActive(false)
+		m.tab.RecalculatePosition()
+	})
 }
 
 // 显示自己, button 和 page 同时显示
 func (m *TPage) Show() {
-	m.button.Show()
-	m.SetActive(true)
-	m.tab.RecalculatePosition()
+	runOnMainThread(func() {
+		m.button.Show()
+		m.SetActive(true)
+		m.tab.RecalculatePosition()
+	})
 }
 
 func (m *TPage) SetOnShow(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onShow = fn
 }
 
 func (m *TPage) SetOnHide(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onHide = fn
 }
 
 func (m *TPage) SetOnClose(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onClose = fn
 }
 
 func (m *TPage) SetOnClick(fn lcl.TNotifyEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.onClick = fn
 }
 
 // 是否进入关闭按钮
 func (m *TPage) IsEnterClose() bool {
-	return m.button.isEnterClose
+	return m.button.enterClose()
 }
 
 func (m *TPage) initEvent() {
 	m.button.SetOnClick(func(sender lcl.IObject) {
-		// 关闭按钮位置, 不触发事件
-		if m.IsEnterClose() {
+		// 动作图标位置, 不触发事件
+		if m.button.EnteredZone() != nil {
 			return
 		}
 		m.tab.HideAllActivated()
 		m.SetActive(true)
-		if m.onClick != nil {
-			m.onClick(sender)
+		m.lock.RLock()
+		onClick := m.onClick
+		m.lock.RUnlock()
+		if onClick != nil {
+			onClick(sender)
 		}
 	})
 	m.button.SetOnCloseClick(func(sender lcl.IObject) {
-		if m.tab.deleting {
+		m.tab.lock.Lock()
+		deleting := m.tab.deleting
+		m.tab.deleting = true
+		m.tab.lock.Unlock()
+		if deleting {
 			return
 		}
-		m.tab.deleting = true
 		m.Close()
 	})
+	m.button.SetOnContextMenu(func(sender lcl.IObject, zone THitZone, mousePos types.TPoint, handled *bool) {
+		m.lock.RLock()
+		fn := m.onTabContextMenu
+		m.lock.RUnlock()
+		if fn == nil {
+			m.tab.lock.RLock()
+			fn = m.tab.onTabContextMenu
+			m.tab.lock.RUnlock()
+		}
+		if fn != nil {
+			fn(m, zone, mousePos, handled)
+		}
+	})
 	m.SetOnResize(func(sender lcl.IObject) {
-		// 滚动导航按钮 位置调整
-		m.tab.scrollBtnPosition()
+		// 宽度变化后重新计算滚动范围和滚动导航按钮, 所有页的内容区同时变化, 只在当前页处理
+		if m.Active() {
+			m.tab.recalculatePosition()
+		} else {
+			m.tab.scrollBtnPosition()
+		}
 	})
 }
 
@@ -431,11 +713,72 @@ func (m *TPage) Button() *TButton {
 }
 
 func (m *TPage) Close() {
-	m.button.isEnterClose = true
+	m.button.setEnterZone(m.button.closeZone())
 	lcl.RunOnMainThreadAsync(func(id uint32) {
-		m.Remove()
-		if m.onClose != nil {
-			m.onClose(m.button)
+		m.lock.RLock()
+		onClose := m.onClose
+		m.lock.RUnlock()
+		m.remove()
+		if onClose != nil {
+			onClose(m.button)
+		}
+	})
+}
+
+// SetTabPopupMenu 设置所有页签的右键菜单, 页签单独设置的菜单优先
+//
+//	菜单弹出时 PopupComponent 为页签按钮
+func (m *TTab) SetTabPopupMenu(menu lcl.IPopupMenu) {
+	m.lock.Lock()
+	m.tabPopupMenu = menu
+	m.lock.Unlock()
+	for _, page := range m.Pages() {
+		page.applyTabPopupMenu()
+	}
+}
+
+// SetOnTabContextMenu 设置所有页签的上下文菜单事件, sender 为 TPage, 页签单独设置的事件优先
+func (m *TTab) SetOnTabContextMenu(fn TContextMenuEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onTabContextMenu = fn
+}
+
+// SetTabPopupMenu 设置页签的右键菜单, nil 时使用 TTab 的设置
+//
+//	页的 SetPopupMenu 是内容区的菜单
+func (m *TPage) SetTabPopupMenu(menu lcl.IPopupMenu) {
+	m.lock.Lock()
+	m.tabPopupMenu = menu
+	m.lock.Unlock()
+	m.applyTabPopupMenu()
+}
+
+// SetOnTabContextMenu 设置页签的上下文菜单事件, sender 为 TPage, nil 时使用 TTab 的设置
+func (m *TPage) SetOnTabContextMenu(fn TContextMenuEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onTabContextMenu = fn
+}
+
+// ShowContextMenu 显示页签的上下文菜单, 用于键盘菜单键
+func (m *TPage) ShowContextMenu() {
+	m.button.ShowContextMenu()
+}
+
+// applyTabPopupMenu 把页签或 TTab 的右键菜单设置到页签按钮
+func (m *TPage) applyTabPopupMenu() {
+	m.lock.RLock()
+	menu := m.tabPopupMenu
+	m.lock.RUnlock()
+	if menu == nil {
+		m.tab.lock.RLock()
+		menu = m.tab.tabPopupMenu
+		m.tab.lock.RUnlock()
+	}
+	runOnMainThread(func() {
+		if m.button.IsValid() {
+			m.button.SetPopupMenu(menu)
 		}
 	})
 }
@@ -0,0 +1,404 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"time"
+)
+
+// 拖动页签: 移动超过该距离后开始拖动, 避免点击时轻微移动触发拖动
+const dragThreshold = int32(5)
+
+// TReorderEvent 页签拖动排序事件, oldIndex 和 newIndex 为页在 Pages 中的索引
+type TReorderEvent func(sender lcl.IObject, page *TPage, oldIndex, newIndex int)
+
+// tMouseTracker 容器跟踪按钮的左键操作, 例如拖动页签, 在按钮处理之后调用
+type tMouseTracker interface {
+	trackDown(button *TButton, X, Y int32)
+	trackMove(button *TButton, X, Y int32)
+	// trackUp 返回 true 时表示拖动结束, 按钮不再处理抬起
+	trackUp(button *TButton, X, Y int32) bool
+}
+
+// tTabDrag 正在拖动的页签, 只在主线程访问
+type tTabDrag struct {
+	page      *TPage
+	index     int   // 开始拖动时的索引
+	startX    int32 // 按下时鼠标位置, TTab 坐标
+	startY    int32
+	grab      int32 // 鼠标在按钮中排列方向上的位置
+	mouseX    int32 // 当前鼠标位置, TTab 坐标
+	mouseY    int32
+	dragging  bool
+	detached  bool  // 已离开页签栏, 松开时拖出
+	scrollDir int32 // 自动滚动方向, 0 不滚动, 1 向左(上), 2 向右(下)
+}
+
+// SetOnReorder 设置页签拖动排序事件, 松开鼠标后位置发生变化时触发
+func (m *TTab) SetOnReorder(fn TReorderEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onReorder = fn
+}
+
+// SetReorderable 设置是否可以拖动页签排序, 默认可以
+func (m *TTab) SetReorderable(reorderable bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.fixedOrder = !reorderable
+}
+
+// Reorderable 返回是否可以拖动页签排序
+func (m *TTab) Reorderable() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return !m.fixedOrder
+}
+
+// IndexOf 返回页的索引, 不属于该 TTab 时返回 -1
+func (m *TTab) IndexOf(page *TPage) int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for i, p := range m.pages {
+		if p == page {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetLockedPosition 设置页签位置是否锁定, 锁定的页签不能拖动, 其它页签也不能越过它
+func (m *TPage) SetLockedPosition(locked bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lockedPosition = locked
+}
+
+// LockedPosition 返回页签位置是否锁定
+func (m *TPage) LockedPosition() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.lockedPosition
+}
+
+// pageOf 返回按钮所属的页
+func (m *TTab) pageOf(button *TButton) *TPage {
+	for _, page := range m.Pages() {
+		if page.button == button {
+			return page
+		}
+	}
+	return nil
+}
+
+func (m *TTab) trackDown(button *TButton, X, Y int32) {
+	page := m.pageOf(button)
+	if page == nil || page.LockedPosition() || !m.Reorderable() {
+		return
+	}
+	x, y := button.Left()+X, button.Top()+Y
+	grab, _ := m.mainCoord(X, Y)
+	m.drag = &tTabDrag{page: page, index: m.IndexOf(page), startX: x, startY: y, grab: grab, mouseX: x, mouseY: y}
+}
+
+func (m *TTab) trackMove(button *TButton, X, Y int32) {
+	d := m.drag
+	if d == nil || d.page.button != button {
+		return
+	}
+	// 鼠标位置换算为 TTab 坐标
+	d.mouseX, d.mouseY = button.Left()+X, button.Top()+Y
+	if !d.dragging {
+		if abs32(d.mouseX-d.startX) < dragThreshold && abs32(d.mouseY-d.startY) < dragThreshold {
+			return
+		}
+		d.dragging = true
+		// 拖动后抬起不触发点击
+		button.cancelClick()
+		button.HideHint()
+		button.BringToFront()
+		m.scrollBtnPosition()
+	}
+	// 厚度方向离开页签栏时准备拖出, 不再排序
+	_, cross := m.mainCoord(d.mouseX, d.mouseY)
+	_, start := m.mainCoord(button.Left(), button.Top())
+	_, thickness := m.mainCoord(button.Width(), button.Height())
+	d.detached = m.TearOff() && (cross < start-tearOffDistance || cross > start+thickness+tearOffDistance)
+	if d.detached {
+		lcl.Screen.SetCursor(types.CrDrag)
+		m.setTriggerScrollStop(true)
+		d.scrollDir = 0
+		return
+	}
+	// 多行时不排序
+	if m.multiRow {
+		return
+	}
+	m.dragUpdate()
+	if !d.page.Pinned() {
+		m.dragAutoScroll()
+	}
+}
+
+func (m *TTab) trackUp(button *TButton, X, Y int32) bool {
+	d := m.drag
+	if d == nil || d.page.button != button {
+		return false
+	}
+	m.drag = nil
+	if !d.dragging {
+		return false
+	}
+	m.setTriggerScrollStop(true)
+	// 在组标签上松开时加入该组, 否则按相邻的页签加入或离开组
+	if !d.detached && !m.dropOnChip(d.page, d.mouseX, d.mouseY) {
+		m.regroup(d.page)
+	}
+	m.recalculatePosition()
+	if d.detached {
+		lcl.Screen.SetCursor(types.CrDefault)
+		// 按钮的鼠标事件结束后再移动页, 拖入其它 TTab 或新窗口
+		pos := button.ClientToScreen(types.TPoint{X: X, Y: Y})
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if m.IsValid() {
+				m.dropPage(d.page, pos, d.grab)
+			}
+		})
+		return true
+	}
+	// 拖动的页签成为当前页
+	m.HideAllActivated()
+	d.page.SetActive(true)
+	newIndex := m.IndexOf(d.page)
+	m.lock.RLock()
+	fn := m.onReorder
+	m.lock.RUnlock()
+	if newIndex != d.index && fn != nil {
+		fn(m, d.page, d.index, newIndex)
+	}
+	return true
+}
+
+// dragUpdate 移动拖动中的按钮, 越过相邻页签的中线时交换位置, 在主线程执行
+func (m *TTab) dragUpdate() {
+	d := m.drag
+	if d == nil || !d.dragging {
+		return
+	}
+	button := d.page.button
+	mouse, _ := m.mainCoord(d.mouseX, d.mouseY)
+	pos := mouse - d.grab
+	// 固定的页签在固定区域内拖动
+	minPos, maxPos := m.stripStart(), m.stripEnd()-m.btnLen(button)
+	pinned := d.page.Pinned()
+	if pinned {
+		minPos, maxPos = 0, m.pinnedWidth-m.btnLen(button)
+	}
+	if pos < minPos {
+		pos = minPos
+	}
+	if pos > maxPos && maxPos >= minPos {
+		pos = maxPos
+	}
+	m.setBtnPos(button, pos)
+	center := pos + m.btnLen(button)/2
+
+	m.lock.Lock()
+	from := -1
+	for i, p := range m.pages {
+		if p == d.page {
+			from = i
+			break
+		}
+	}
+	if from == -1 {
+		m.lock.Unlock()
+		return
+	}
+	slots := make([]tReorderSlot, len(m.pages))
+	for i, p := range m.pages {
+		if i == from || !p.button.Visible() {
+			continue
+		}
+		slots[i] = tReorderSlot{
+			visible: true,
+			barrier: p.LockedPosition() || p.Pinned() != pinned,
+			mid:     m.slotPos(p) + m.btnLen(p.button)/2,
+		}
+	}
+	to := reorderIndex(slots, from, center)
+	if to != from {
+		m.pages = movePage(m.pages, from, to)
+	}
+	m.lock.Unlock()
+	if to != from {
+		m.recalculatePosition()
+	}
+}
+
+// tReorderSlot 拖动排序时其它页签的位置
+type tReorderSlot struct {
+	visible bool  // 隐藏的页签跳过
+	barrier bool  // 锁定位置或固定状态与拖动的页签不同, 不能越过
+	mid     int32 // 排列方向上的中线
+}
+
+// reorderIndex 返回拖动的页签(索引 from)中线移动到 center 时的新索引
+//
+//	向前(后)越过相邻显示页签的中线时移动到该页签的位置, 遇到 barrier 停止, slots[from] 不使用
+func reorderIndex(slots []tReorderSlot, from int, center int32) int {
+	to := from
+	for i := from - 1; i >= 0; i-- {
+		s := slots[i]
+		if !s.visible {
+			continue
+		}
+		if s.barrier || center >= s.mid {
+			break
+		}
+		to = i
+	}
+	if to != from {
+		return to
+	}
+	for i := from + 1; i < len(slots); i++ {
+		s := slots[i]
+		if !s.visible {
+			continue
+		}
+		if s.barrier || center <= s.mid {
+			break
+		}
+		to = i
+	}
+	return to
+}
+
+// movePage 返回把 from 位置的页移动到 to 位置后的页列表, 不修改 pages
+func movePage(pages []*TPage, from, to int) []*TPage {
+	page := pages[from]
+	moved := append(pages[:from:from], pages[from+1:]...)
+	return append(moved[:to:to], append([]*TPage{page}, moved[to:]...)...)
+}
+
+// slotPos 返回页签在布局中排列方向上的位置, 滑动中的按钮返回目标位置, 在主线程执行
+func (m *TTab) slotPos(page *TPage) int32 {
+	if pos, ok := m.slides[page.button]; ok {
+		return pos
+	}
+	return m.btnPos(page.button)
+}
+
+// dragAutoScroll 拖动到滚动按钮附近时自动滚动页签, 在主线程执行
+func (m *TTab) dragAutoScroll() {
+	d := m.drag
+	dir := int32(0)
+	mouse, _ := m.mainCoord(d.mouseX, d.mouseY)
+	if mouse < m.stripStart()+scrollBtnMargin {
+		dir = 1
+	} else if mouse > m.stripEnd()-scrollBtnMargin {
+		dir = 2
+	}
+	if dir == d.scrollDir {
+		return
+	}
+	d.scrollDir = dir
+	m.setTriggerScrollStop(true)
+	if dir == 0 {
+		return
+	}
+	m.setTriggerScrollStop(false)
+	m.dragScrollLoop(dir)
+}
+
+// dragScrollLoop 拖动时连续滚动, 方向变化或拖动结束时停止
+func (m *TTab) dragScrollLoop(dir int32) {
+	m.lock.RLock()
+	stop := m.triggerScrollStop
+	m.lock.RUnlock()
+	d := m.drag
+	if stop || d == nil || d.scrollDir != dir {
+		return
+	}
+	if dir == 1 {
+		m.scrollLeft()
+	} else {
+		m.scrollRight()
+	}
+	// 滚动后鼠标下的页签变化, 重新计算拖动位置
+	m.dragUpdate()
+	time.AfterFunc(time.Second/30, func() {
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			m.dragScrollLoop(dir)
+		})
+	})
+}
+
+// placeButton 设置页签按钮位置, 在主线程执行
+//
+//	拖动中的按钮跟随鼠标不移动, 拖动时其它按钮滑动到新位置让开
+func (m *TTab) placeButton(button *TButton, pos int32) {
+	d := m.drag
+	if d != nil && d.dragging {
+		if button == d.page.button {
+			return
+		}
+		m.slideTo(button, pos)
+		return
+	}
+	delete(m.slides, button)
+	m.setBtnPos(button, pos)
+}
+
+// slideTo 按钮沿排列方向滑动到 pos, 每帧移动剩余距离的一半, 在主线程执行
+func (m *TTab) slideTo(button *TButton, pos int32) {
+	if m.btnPos(button) == pos {
+		delete(m.slides, button)
+		return
+	}
+	if m.slides == nil {
+		m.slides = make(map[*TButton]int32)
+	}
+	m.slides[button] = pos
+	if !m.sliding {
+		m.sliding = true
+		m.slideStep()
+	}
+}
+
+// slideStep 滑动动画的一帧, 在主线程执行
+func (m *TTab) slideStep() {
+	for button, pos := range m.slides {
+		if !button.IsValid() {
+			delete(m.slides, button)
+			continue
+		}
+		diff := pos - m.btnPos(button)
+		if abs32(diff) <= 1 {
+			m.setBtnPos(button, pos)
+			delete(m.slides, button)
+			continue
+		}
+		m.setBtnPos(button, m.btnPos(button)+diff/2)
+	}
+	if len(m.slides) == 0 {
+		m.sliding = false
+		return
+	}
+	time.AfterFunc(fadeFrameInterval, func() {
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if m.IsValid() {
+				m.slideStep()
+			} else {
+				m.sliding = false
+			}
+		})
+	})
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
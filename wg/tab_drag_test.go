@@ -0,0 +1,147 @@
+package wg
+
+import "testing"
+
+// reorderSlots 返回宽度 100 依次排列的页签位置, hidden 和 barriers 为隐藏和不能越过的页签索引
+func reorderSlots(count int, hidden, barriers []int) []tReorderSlot {
+	slots := make([]tReorderSlot, count)
+	for i := range slots {
+		slots[i] = tReorderSlot{visible: true, mid: int32(i*100 + 50)}
+	}
+	for _, i := range hidden {
+		slots[i].visible = false
+	}
+	for _, i := range barriers {
+		slots[i].barrier = true
+	}
+	return slots
+}
+
+func TestReorderIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		hidden   []int
+		barriers []int
+		center   int32
+		want     int
+	}{
+		{"in place", nil, nil, 250, 2},
+		{"before previous midpoint", nil, nil, 151, 2},
+		{"on previous midpoint", nil, nil, 150, 2},
+		{"past previous midpoint", nil, nil, 149, 1},
+		{"past two", nil, nil, 40, 0},
+		{"before next midpoint", nil, nil, 349, 2},
+		{"on next midpoint", nil, nil, 350, 2},
+		{"past next midpoint", nil, nil, 351, 3},
+		{"past last", nil, nil, 460, 4},
+		{"skip hidden", []int{1}, nil, 40, 0},
+		{"hidden is not crossed", []int{1}, nil, 140, 2},
+		{"skip hidden forward", []int{3}, nil, 460, 4},
+		{"locked neighbour", nil, []int{1}, 40, 2},
+		{"stop at locked", nil, []int{0}, 40, 1},
+		{"stop at pinned forward", nil, []int{4}, 460, 3},
+		{"hidden then locked", []int{1}, []int{0}, 40, 2},
+	}
+	for _, tt := range tests {
+		slots := reorderSlots(5, tt.hidden, tt.barriers)
+		if got := reorderIndex(slots, 2, tt.center); got != tt.want {
+			t.Errorf("%s: reorderIndex(center %d) = %d, want %d", tt.name, tt.center, got, tt.want)
+		}
+	}
+	// 只有一个页签
+	if got := reorderIndex(reorderSlots(1, nil, nil), 0, 500); got != 0 {
+		t.Errorf("single tab = %d, want 0", got)
+	}
+}
+
+func TestMovePage(t *testing.T) {
+	a, b, c, d := &TPage{}, &TPage{}, &TPage{}, &TPage{}
+	tests := []struct {
+		name     string
+		from, to int
+		want     []*TPage
+	}{
+		{"forward", 0, 2, []*TPage{b, c, a, d}},
+		{"to last", 1, 3, []*TPage{a, c, d, b}},
+		{"backward", 3, 1, []*TPage{a, d, b, c}},
+		{"to first", 2, 0, []*TPage{c, a, b, d}},
+		{"same", 1, 1, []*TPage{a, b, c, d}},
+	}
+	for _, tt := range tests {
+		pages := []*TPage{a, b, c, d}
+		got := movePage(pages, tt.from, tt.to)
+		if !samePages(got, tt.want) {
+			t.Errorf("%s: movePage(%d, %d) order wrong", tt.name, tt.from, tt.to)
+		}
+		if !samePages(pages, []*TPage{a, b, c, d}) {
+			t.Errorf("%s: movePage modified pages", tt.name)
+		}
+	}
+}
+
+// dragPages 模拟拖动 pages[from], 中线依次移动到 centers, 其它页签按当前顺序宽度 100 排列, 返回松开时的索引
+func dragPages(pages []*TPage, from int, locked *TPage, centers ...int32) int {
+	dragged := pages[from]
+	for _, center := range centers {
+		var barriers []int
+		if locked != nil {
+			barriers = []int{pageIndex(pages, locked)}
+		}
+		index := pageIndex(pages, dragged)
+		if to := reorderIndex(reorderSlots(len(pages), nil, barriers), index, center); to != index {
+			pages = movePage(pages, index, to)
+		}
+	}
+	return pageIndex(pages, dragged)
+}
+
+// TestReorderDrag OnReorder 的索引为开始拖动时和松开时的索引, 一次拖动中可以多次交换位置
+func TestReorderDrag(t *testing.T) {
+	newPages := func() []*TPage {
+		return []*TPage{{}, {}, {}, {}, {}}
+	}
+	tests := []struct {
+		name     string
+		from     int
+		locked   int // 锁定的页签, -1 没有
+		centers  []int32
+		newIndex int
+	}{
+		{"right", 1, -1, []int32{260, 360}, 3},
+		{"right and back", 1, -1, []int32{260, 460, 330, 140}, 1},
+		{"left", 4, -1, []int32{340, 40}, 0},
+		{"stop before locked", 0, 3, []int32{160, 260, 460}, 2},
+		{"locked behind", 4, 3, []int32{40}, 4},
+	}
+	for _, tt := range tests {
+		pages := newPages()
+		var locked *TPage
+		if tt.locked >= 0 {
+			locked = pages[tt.locked]
+		}
+		if got := dragPages(pages, tt.from, locked, tt.centers...); got != tt.newIndex {
+			t.Errorf("%s: OnReorder(%d, %d), want (%d, %d)", tt.name, tt.from, got, tt.from, tt.newIndex)
+		}
+	}
+}
+
+func pageIndex(pages []*TPage, page *TPage) int {
+	for i, p := range pages {
+		if p == page {
+			return i
+		}
+	}
+	return -1
+}
+
+func samePages(a, b []*TPage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
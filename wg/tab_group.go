@@ -0,0 +1,473 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"sync"
+)
+
+// 成员页签组颜色线的宽度
+var groupLineWidth = int32(2)
+
+// TPageGroupEvent 页加入或离开页签组事件, 离开时 group 为 nil
+type TPageGroupEvent func(sender lcl.IObject, page *TPage, group *TTabGroup)
+
+// TTabGroup 页签组
+//
+//	组标签显示在第一个成员页签之前, 成员页签在页签栏中连续排列, 靠内容区一侧显示组颜色线;
+//	单击组标签折叠或展开, 折叠时只显示组标签; 固定的页签不能加入组
+type TTabGroup struct {
+	lock      sync.RWMutex // 保护下列状态
+	tab       *TTab
+	chip      *TButton
+	name      string
+	color     colors.TColor
+	collapsed bool
+	hidden    []*TPage // 折叠时隐藏的成员页签, 展开时恢复, 只在主线程访问
+}
+
+// tStripItem 页签栏中按顺序排列的按钮, 页签或组标签
+type tStripItem struct {
+	button *TButton
+	page   *TPage // 组标签为 nil
+}
+
+// pinned 返回是否为固定的页签
+func (m tStripItem) pinned() bool {
+	return m.page != nil && m.page.Pinned()
+}
+
+// NewGroup 创建页签组, 添加成员后显示组标签
+//
+//	创建 LCL 控件, 只能在主线程调用
+func (m *TTab) NewGroup(name string, color colors.TColor) *TTabGroup {
+	group := &TTabGroup{tab: m, name: name, color: color}
+	chip := NewButton(m)
+	chip.SetCaption(name)
+	chip.SetAutoSize(true)
+	chip.SetAutoTextColor(true)
+	chip.Font().SetSize(9)
+	chip.Font().SetStyle(types.NewSet(types.FsBold))
+	chip.SetRadius(6)
+	chip.SetAlpha(255)
+	chip.SetHeight(defaultHeight)
+	chip.SetVisible(false)
+	chip.setAccessibleRole(ArButton)
+	chip.setOpacity(m.Opacity())
+	m.applyButtonPosition(chip)
+	chip.SetParent(m)
+	m.initStripWheel(chip, false)
+	chip.SetOnClick(func(sender lcl.IObject) {
+		group.SetCollapsed(!group.Collapsed())
+	})
+	group.chip = chip
+	group.applyColor()
+	m.lock.Lock()
+	m.groups = append(m.groups, group)
+	m.lock.Unlock()
+	return group
+}
+
+// Groups 返回页签组列表
+func (m *TTab) Groups() []*TTabGroup {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	groups := make([]*TTabGroup, len(m.groups))
+	copy(groups, m.groups)
+	return groups
+}
+
+// SetOnPageGroup 设置页加入或离开页签组事件, 包括拖动页签加入或移出组, 页关闭或移到其它 TTab 时不触发
+func (m *TTab) SetOnPageGroup(fn TPageGroupEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onPageGroup = fn
+}
+
+// Group 返回页所在的页签组, 不在组中时返回 nil
+func (m *TPage) Group() *TTabGroup {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.group
+}
+
+// Tab 返回组所属的 TTab
+func (m *TTabGroup) Tab() *TTab {
+	return m.tab
+}
+
+// Chip 返回组标签按钮, 可以设置字体和提示
+func (m *TTabGroup) Chip() *TButton {
+	return m.chip
+}
+
+// SetName 设置组名称
+func (m *TTabGroup) SetName(name string) {
+	m.lock.Lock()
+	m.name = name
+	m.lock.Unlock()
+	m.chip.SetCaption(name)
+	lcl.RunOnMainThreadAsync(func(id uint32) {
+		m.tab.RecalculatePosition()
+	})
+}
+
+// Name 返回组名称
+func (m *TTabGroup) Name() string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.name
+}
+
+// SetColor 设置组颜色, 用于组标签背景和成员页签的颜色线
+func (m *TTabGroup) SetColor(color colors.TColor) {
+	m.lock.Lock()
+	m.color = color
+	m.lock.Unlock()
+	runOnMainThread(func() {
+		m.applyColor()
+		for _, page := range m.Pages() {
+			m.tab.applyGroupMark(page)
+		}
+	})
+}
+
+// Color 返回组颜色
+func (m *TTabGroup) Color() colors.TColor {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.color
+}
+
+// Pages 返回按页签顺序排列的成员页
+func (m *TTabGroup) Pages() []*TPage {
+	var pages []*TPage
+	for _, page := range m.tab.Pages() {
+		if page.Group() == m {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// AddPage 把页加入组, 页签移到最后一个成员之后, 已在其它组中时先离开原来的组
+//
+//	组折叠时页签随组隐藏, 固定的页签不能加入组
+func (m *TTabGroup) AddPage(page *TPage) {
+	runOnMainThread(func() {
+		if page.tab != m.tab || page.Pinned() || page.Group() == m {
+			return
+		}
+		m.tab.movePageToGroup(page, m)
+		m.tab.setPageGroup(page, m)
+		m.tab.recalculatePosition()
+	})
+}
+
+// RemovePage 把页移出组, 页签移到组的最后一个成员之后
+func (m *TTabGroup) RemovePage(page *TPage) {
+	runOnMainThread(func() {
+		if page.Group() != m {
+			return
+		}
+		m.tab.movePageToGroup(page, m)
+		m.tab.setPageGroup(page, nil)
+		m.tab.recalculatePosition()
+	})
+}
+
+// Ungroup 解散组, 所有成员离开组, 删除组标签
+func (m *TTabGroup) Ungroup() {
+	runOnMainThread(func() {
+		tab := m.tab
+		m.SetCollapsed(false)
+		for _, page := range m.Pages() {
+			tab.setPageGroup(page, nil)
+		}
+		tab.lock.Lock()
+		for i, g := range tab.groups {
+			if g == m {
+				tab.groups = append(tab.groups[:i:i], tab.groups[i+1:]...)
+				break
+			}
+		}
+		tab.lock.Unlock()
+		if m.chip.IsValid() {
+			m.chip.Free()
+		}
+		tab.recalculatePosition()
+	})
+}
+
+// SetCollapsed 设置组是否折叠, 折叠时只显示组标签, 当前页在组中时激活组外相邻的页
+func (m *TTabGroup) SetCollapsed(collapsed bool) {
+	runOnMainThread(func() {
+		m.lock.Lock()
+		changed := m.collapsed != collapsed
+		m.collapsed = collapsed
+		m.lock.Unlock()
+		if !changed || !m.tab.IsValid() {
+			return
+		}
+		if collapsed {
+			pages := m.Pages()
+			active := false
+			for _, page := range pages {
+				if page.button.Visible() {
+					page.button.SetVisible(false)
+					m.hidden = append(m.hidden, page)
+				}
+				active = active || page.Active()
+			}
+			if active {
+				m.tab.activateOutside(m)
+			}
+		} else {
+			for _, page := range m.hidden {
+				if page.tab == m.tab && page.IsValid() {
+					page.button.SetVisible(true)
+				}
+			}
+			m.hidden = nil
+		}
+		m.tab.recalculatePosition()
+	})
+}
+
+// Collapsed 返回组是否折叠
+func (m *TTabGroup) Collapsed() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.collapsed
+}
+
+// applyColor 按组颜色设置组标签颜色, 在主线程执行
+func (m *TTabGroup) applyColor() {
+	color := m.Color()
+	m.chip.SetDefaultColor(color, color)
+	m.chip.SetEnterColor(LightenColor(color, 0.1), LightenColor(color, 0.1))
+	m.chip.SetDownColor(DarkenColor(color, 0.1), DarkenColor(color, 0.1))
+	m.chip.SetBorderColor(BbdNone, color)
+}
+
+// unhide 页离开折叠的组时恢复显示页签, 在主线程执行
+func (m *TTabGroup) unhide(page *TPage) {
+	for i, p := range m.hidden {
+		if p == page {
+			m.hidden = append(m.hidden[:i:i], m.hidden[i+1:]...)
+			if page.IsValid() {
+				page.button.SetVisible(true)
+			}
+			return
+		}
+	}
+}
+
+// setPageGroup 设置页所在的组, 变化时更新颜色线和折叠状态并触发 OnPageGroup, 在主线程执行
+func (m *TTab) setPageGroup(page *TPage, group *TTabGroup) {
+	old := page.Group()
+	if old == group {
+		return
+	}
+	m.clearPageGroup(page)
+	page.lock.Lock()
+	page.group = group
+	page.lock.Unlock()
+	m.applyGroupMark(page)
+	// 加入折叠的组时页签随组隐藏
+	if group != nil && group.Collapsed() && page.button.Visible() {
+		page.button.SetVisible(false)
+		group.hidden = append(group.hidden, page)
+		if page.Active() {
+			m.activateOutside(group)
+		}
+	}
+	m.lock.RLock()
+	fn := m.onPageGroup
+	m.lock.RUnlock()
+	if fn != nil {
+		fn(m, page, group)
+	}
+}
+
+// clearPageGroup 页离开所在的组, 不触发事件, 在主线程执行
+func (m *TTab) clearPageGroup(page *TPage) {
+	page.lock.Lock()
+	group := page.group
+	page.group = nil
+	page.lock.Unlock()
+	if group != nil {
+		group.unhide(page)
+		page.button.setGroupMark(BbdNone, 0)
+	}
+}
+
+// applyGroupMark 在成员页签靠内容区一侧绘制组颜色线, 在主线程执行
+func (m *TTab) applyGroupMark(page *TPage) {
+	group := page.Group()
+	if group == nil {
+		page.button.setGroupMark(BbdNone, 0)
+		return
+	}
+	edge := BbdBottom
+	switch m.position {
+	case TpBottom:
+		edge = BbdTop
+	case TpLeft:
+		edge = BbdRight
+	case TpRight:
+		edge = BbdLeft
+	}
+	page.button.setGroupMark(edge, group.Color())
+}
+
+// movePageToGroup 把页移到组的最后一个成员之后, 组没有其它成员时位置不变, 在主线程执行
+func (m *TTab) movePageToGroup(page *TPage, group *TTabGroup) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	last := -1
+	for i, p := range m.pages {
+		if p != page && p.Group() == group {
+			last = i
+		}
+	}
+	if last < 0 {
+		return
+	}
+	pages := make([]*TPage, 0, len(m.pages))
+	for i, p := range m.pages {
+		if p != page {
+			pages = append(pages, p)
+		}
+		if i == last {
+			pages = append(pages, page)
+		}
+	}
+	m.pages = pages
+}
+
+// regroup 页签拖动或移入后按相邻页签调整所在的组, 在主线程执行
+//
+//	前后相邻的页在同一组时加入该组, 原来所在的组和相邻的页都不同时离开组
+func (m *TTab) regroup(page *TPage) {
+	if page.Pinned() {
+		return
+	}
+	pages := m.Pages()
+	var prev, next *TTabGroup
+	for i, p := range pages {
+		if p != page {
+			continue
+		}
+		if i > 0 {
+			prev = pages[i-1].Group()
+		}
+		if i < len(pages)-1 {
+			next = pages[i+1].Group()
+		}
+	}
+	group := page.Group()
+	switch {
+	case prev != nil && prev == next:
+		m.setPageGroup(page, prev)
+	case group != nil && group != prev && group != next:
+		m.setPageGroup(page, nil)
+	}
+}
+
+// groupChipAt 返回 TTab 坐标所在的组标签的组, 没有时返回 nil
+func (m *TTab) groupChipAt(x, y int32) *TTabGroup {
+	pt := types.TPoint{X: x, Y: y}
+	for _, group := range m.Groups() {
+		if chip := group.chip; chip.Visible() && chip.BoundsRect().PtInRect(pt) {
+			return group
+		}
+	}
+	return nil
+}
+
+// dropOnChip 拖动的页签在组标签上松开时加入该组, 返回是否加入, 在主线程执行
+func (m *TTab) dropOnChip(page *TPage, x, y int32) bool {
+	group := m.groupChipAt(x, y)
+	if group == nil || page.Pinned() {
+		return false
+	}
+	if page.Group() != group {
+		m.movePageToGroup(page, group)
+		m.setPageGroup(page, group)
+	}
+	return true
+}
+
+// activateOutside 激活组外与组相邻的页, 优先组后面的页, 在主线程执行
+func (m *TTab) activateOutside(group *TTabGroup) {
+	pages := m.visiblePages()
+	var before, after *TPage
+	inGroup := false
+	for _, page := range pages {
+		if page.Group() == group {
+			inGroup = true
+		} else if !inGroup {
+			before = page
+		} else if after == nil {
+			after = page
+		}
+	}
+	if after == nil {
+		after = before
+	}
+	if after != nil {
+		m.HideAllActivated()
+		after.SetActive(true)
+	}
+}
+
+// stripItems 返回按顺序排列的显示的页签和组标签, 组标签在第一个成员之前, 并设置组标签是否显示
+func (m *TTab) stripItems(pages []*TPage) []tStripItem {
+	var items []tStripItem
+	shown := make(map[*TTabGroup]bool)
+	for _, page := range pages {
+		if group := page.Group(); group != nil && !shown[group] {
+			shown[group] = true
+			items = append(items, tStripItem{button: group.chip})
+		}
+		if page.button.Visible() {
+			items = append(items, tStripItem{button: page.button, page: page})
+		}
+	}
+	for _, group := range m.Groups() {
+		if visible := shown[group]; group.chip.Visible() != visible {
+			group.chip.SetVisible(visible)
+		}
+	}
+	return items
+}
+
+// setGroupMark 设置作为页签组成员时颜色线所在的边, BbdNone 不绘制
+func (m *TButton) setGroupMark(edge TButtonBorderDirection, color colors.TColor) {
+	m.lock.Lock()
+	changed := m.groupEdge != edge || m.groupColor != color
+	m.groupEdge, m.groupColor = edge, color
+	m.lock.Unlock()
+	if changed {
+		m.invalidate()
+	}
+}
+
+// drawGroupMark 绘制页签组颜色线
+func (m *TButton) drawGroupMark(canvas lcl.ICanvas, rect types.TRect, edge TButtonBorderDirection, color colors.TColor) {
+	brush := canvas.BrushToBrush()
+	brush.SetStyle(types.BsSolid)
+	brush.SetColor(color)
+	switch edge {
+	case BbdTop:
+		canvas.FillRectWithIntX4(rect.Left+iconMargin, rect.Top, rect.Right-iconMargin, rect.Top+groupLineWidth)
+	case BbdLeft:
+		canvas.FillRectWithIntX4(rect.Left, rect.Top+iconMargin, rect.Left+groupLineWidth, rect.Bottom-iconMargin)
+	case BbdRight:
+		canvas.FillRectWithIntX4(rect.Right-groupLineWidth, rect.Top+iconMargin, rect.Right, rect.Bottom-iconMargin)
+	default:
+		canvas.FillRectWithIntX4(rect.Left+iconMargin, rect.Bottom-groupLineWidth, rect.Right-iconMargin, rect.Bottom)
+	}
+}
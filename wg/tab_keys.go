@@ -0,0 +1,521 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/keys"
+)
+
+// 页签切换窗口
+var (
+	switcherWidth    = int32(320)
+	switcherMaxItems = 15
+)
+
+// TTabCommand 页签键盘命令
+type TTabCommand int32
+
+const (
+	TcCycleNext  TTabCommand = iota // 切换到下一页, 可以按最近使用顺序并显示切换窗口, 默认 Ctrl+Tab
+	TcCyclePrev                     // 切换到上一页, 默认 Ctrl+Shift+Tab
+	TcNextPage                      // 按页签顺序激活下一页, 默认 Ctrl+PageDown
+	TcPrevPage                      // 按页签顺序激活上一页, 默认 Ctrl+PageUp
+	TcClosePage                     // 关闭当前页, 默认 Ctrl+W, Ctrl+F4
+	TcPage1                         // 激活第 1 个页签, 默认 Ctrl+1, 到 TcPage8 依次为 Ctrl+2 到 Ctrl+8
+	TcPage2                         //
+	TcPage3                         //
+	TcPage4                         //
+	TcPage5                         //
+	TcPage6                         //
+	TcPage7                         //
+	TcPage8                         //
+	TcLastPage                      // 激活最后一个页签, 默认 Ctrl+9
+	TcStripPrev                     // 页签栏获得焦点时激活上一页, 默认 Left, Up
+	TcStripNext                     // 页签栏获得焦点时激活下一页, 默认 Right, Down
+	TcStripFirst                    // 页签栏获得焦点时激活第一页, 默认 Home
+	TcStripLast                     // 页签栏获得焦点时激活最后一页, 默认 End
+)
+
+// 按顺序匹配快捷键的命令
+const tabCommandCount = int(TcStripLast) + 1
+
+// TTabShortcut 页签快捷键
+//
+//	Key 为虚拟键码 keys.Vk*, Shift 只比较 SsShift, SsAlt, SsCtrl, SsMeta
+type TTabShortcut struct {
+	Key   uint16
+	Shift types.TShiftState
+}
+
+// NewTabShortcut 创建页签快捷键, shift 为 types.SsCtrl 等
+func NewTabShortcut(key uint16, shift ...int32) TTabShortcut {
+	return TTabShortcut{Key: key, Shift: types.NewSet(shift...)}
+}
+
+// 快捷键比较的修饰键
+var shortcutModifiers = []int32{types.SsShift, types.SsAlt, types.SsCtrl, types.SsMeta}
+
+// match 返回按键是否和快捷键相同
+func (s TTabShortcut) match(key uint16, shift types.TShiftState) bool {
+	if s.Key != key {
+		return false
+	}
+	for _, modifier := range shortcutModifiers {
+		if s.Shift.In(modifier) != shift.In(modifier) {
+			return false
+		}
+	}
+	return true
+}
+
+// holding 返回是否按住 Ctrl, Alt 或 Meta, 按住时连续切换页, 松开后结束
+func holding(shift types.TShiftState) bool {
+	return shift.In(types.SsCtrl) || shift.In(types.SsAlt) || shift.In(types.SsMeta)
+}
+
+// defaultTabShortcuts 返回默认快捷键表
+func defaultTabShortcuts() map[TTabCommand][]TTabShortcut {
+	shortcuts := map[TTabCommand][]TTabShortcut{
+		TcCycleNext:  {NewTabShortcut(keys.VkTab, types.SsCtrl)},
+		TcCyclePrev:  {NewTabShortcut(keys.VkTab, types.SsCtrl, types.SsShift)},
+		TcNextPage:   {NewTabShortcut(keys.VkNext, types.SsCtrl)},
+		TcPrevPage:   {NewTabShortcut(keys.VkPrior, types.SsCtrl)},
+		TcClosePage:  {NewTabShortcut(keys.VkW, types.SsCtrl), NewTabShortcut(keys.VkF4, types.SsCtrl)},
+		TcLastPage:   {NewTabShortcut(keys.Vk9, types.SsCtrl)},
+		TcStripPrev:  {NewTabShortcut(keys.VkLeft), NewTabShortcut(keys.VkUp)},
+		TcStripNext:  {NewTabShortcut(keys.VkRight), NewTabShortcut(keys.VkDown)},
+		TcStripFirst: {NewTabShortcut(keys.VkHome)},
+		TcStripLast:  {NewTabShortcut(keys.VkEnd)},
+	}
+	for i := TTabCommand(0); i <= TcPage8-TcPage1; i++ {
+		shortcuts[TcPage1+i] = []TTabShortcut{NewTabShortcut(keys.Vk1+uint16(i), types.SsCtrl)}
+	}
+	return shortcuts
+}
+
+// SetShortcuts 设置命令的快捷键, 替换原来的快捷键, 不传快捷键时禁用该命令
+func (m *TTab) SetShortcuts(command TTabCommand, shortcuts ...TTabShortcut) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.shortcuts[command] = append([]TTabShortcut(nil), shortcuts...)
+}
+
+// Shortcuts 返回命令的快捷键
+func (m *TTab) Shortcuts(command TTabCommand) []TTabShortcut {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return append([]TTabShortcut(nil), m.shortcuts[command]...)
+}
+
+// ResetShortcuts 恢复默认快捷键
+func (m *TTab) ResetShortcuts() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.shortcuts = defaultTabShortcuts()
+}
+
+// SetMRUOrder 设置 TcCycleNext, TcCyclePrev 是否按最近使用顺序切换, 默认按页签顺序
+//
+//	按住 Ctrl 连续切换时顺序不变, 松开后切换到的页成为最近使用的页
+func (m *TTab) SetMRUOrder(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.mruOrder = enabled
+}
+
+// MRUOrder 返回是否按最近使用顺序切换
+func (m *TTab) MRUOrder() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.mruOrder
+}
+
+// SetSwitcher 设置 TcCycleNext, TcCyclePrev 是否显示切换窗口, 默认不显示
+//
+//	按住 Ctrl 时在 TTab 中央列出所有页签, 再按快捷键或上下键选择, 松开 Ctrl 或单击激活, Esc 取消;
+//	快捷键没有 Ctrl, Alt, Meta 修饰键时不显示
+func (m *TTab) SetSwitcher(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.switcher = enabled
+}
+
+// Switcher 返回是否显示切换窗口
+func (m *TTab) Switcher() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.switcher
+}
+
+// HandleKeyDown 处理页签快捷键, 处理后 key 设置为 0
+//
+//	TTab 获得焦点时自动处理. 焦点在页内容中时, 在窗口(KeyPreview 为 true)的 OnKeyDown 中调用,
+//	同时在 OnKeyUp 中调用 HandleKeyUp
+func (m *TTab) HandleKeyDown(key *uint16, shift types.TShiftState) {
+	runOnMainThread(func() {
+		m.handleKeyDown(key, shift)
+	})
+}
+
+// HandleKeyUp 处理松开按键, 松开 Ctrl 时结束连续切换
+func (m *TTab) HandleKeyUp(key *uint16, shift types.TShiftState) {
+	runOnMainThread(func() {
+		m.handleKeyUp(key, shift)
+	})
+}
+
+// ActivePage 返回当前页, 没有时返回 nil
+func (m *TTab) ActivePage() *TPage {
+	for _, page := range m.Pages() {
+		if page.Active() {
+			return page
+		}
+	}
+	return nil
+}
+
+// initKeys 初始化键盘操作, TTab 获得焦点时在当前页签上绘制焦点框
+func (m *TTab) initKeys() {
+	m.shortcuts = defaultTabShortcuts()
+	m.SetTabStop(true)
+	m.SetOnKeyDown(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		m.handleKeyDown(key, shift)
+	})
+	m.SetOnKeyUp(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		m.handleKeyUp(key, shift)
+	})
+	m.SetOnEnter(func(sender lcl.IObject) {
+		m.updateFocusRect()
+	})
+	m.SetOnExit(func(sender lcl.IObject) {
+		m.updateFocusRect()
+	})
+}
+
+// commandOf 返回按键对应的命令
+func (m *TTab) commandOf(key uint16, shift types.TShiftState) (TTabCommand, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for i := 0; i < tabCommandCount; i++ {
+		command := TTabCommand(i)
+		for _, shortcut := range m.shortcuts[command] {
+			if shortcut.match(key, shift) {
+				return command, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// stripCommand 返回是否为页签栏获得焦点时才处理的命令
+func stripCommand(command TTabCommand) bool {
+	return command >= TcStripPrev && command <= TcStripLast
+}
+
+// handleKeyDown 处理页签快捷键, 在主线程执行
+func (m *TTab) handleKeyDown(key *uint16, shift types.TShiftState) {
+	if *key == 0 || !m.IsValid() {
+		return
+	}
+	command, ok := m.commandOf(*key, shift)
+	if !ok {
+		// 松开修饰键前按下其它键, 结束连续切换
+		if m.cycle != nil && !holding(shift) {
+			m.endCycle(true)
+		}
+		return
+	}
+	if stripCommand(command) && !m.Focused() {
+		return
+	}
+	*key = 0
+	switch command {
+	case TcCycleNext:
+		m.cyclePage(1, holding(shift))
+	case TcCyclePrev:
+		m.cyclePage(-1, holding(shift))
+	case TcNextPage, TcStripNext:
+		m.stepPage(1)
+	case TcPrevPage, TcStripPrev:
+		m.stepPage(-1)
+	case TcClosePage:
+		if page := m.ActivePage(); page != nil {
+			page.Close()
+		}
+	case TcStripFirst:
+		m.jumpPage(0)
+	case TcLastPage, TcStripLast:
+		m.jumpPage(-1)
+	default:
+		m.jumpPage(int(command - TcPage1))
+	}
+}
+
+// handleKeyUp 松开 Ctrl 等修饰键时结束连续切换, 切换窗口自己处理按键, 在主线程执行
+func (m *TTab) handleKeyUp(key *uint16, shift types.TShiftState) {
+	if m.cycle != nil && m.cycle.form == nil && !holding(shift) {
+		m.endCycle(true)
+	}
+}
+
+// visiblePages 返回显示的页
+func (m *TTab) visiblePages() []*TPage {
+	var pages []*TPage
+	for _, page := range m.Pages() {
+		if page.button.Visible() {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// stepPage 按页签顺序激活前后的页, 首尾循环, 在主线程执行
+func (m *TTab) stepPage(step int) {
+	pages := m.visiblePages()
+	if len(pages) == 0 {
+		return
+	}
+	index := -1
+	for i, page := range pages {
+		if page.Active() {
+			index = i
+		}
+	}
+	if index < 0 && step < 0 {
+		index = 0
+	}
+	m.selectPage(pages[(index+step+len(pages))%len(pages)])
+}
+
+// jumpPage 激活第 index 个显示的页, 小于 0 时为最后一个, 在主线程执行
+func (m *TTab) jumpPage(index int) {
+	pages := m.visiblePages()
+	if index < 0 {
+		index = len(pages) - 1
+	}
+	if index >= 0 && index < len(pages) {
+		m.selectPage(pages[index])
+	}
+}
+
+// updateFocusRect TTab 获得焦点时在当前页签上绘制焦点框, 在主线程执行
+func (m *TTab) updateFocusRect() {
+	focused := m.Focused()
+	for _, page := range m.Pages() {
+		page.button.setFocusRect(focused && page.Active())
+	}
+}
+
+// touchMRU 把页移到最近使用列表的开头, 在主线程执行
+func (m *TTab) touchMRU(page *TPage) {
+	m.mru = touchPage(m.mru, page)
+}
+
+// removeMRU 从最近使用列表中删除页, 在主线程执行
+func (m *TTab) removeMRU(page *TPage) {
+	m.mru = withoutPage(m.mru, page)
+}
+
+// mruPages 返回按最近使用顺序排列的显示的页, 没有激活过的页按页签顺序排在最后
+func (m *TTab) mruPages() []*TPage {
+	return mruOrder(m.mru, m.visiblePages())
+}
+
+// touchPage 返回把页移到开头后的最近使用列表
+func touchPage(mru []*TPage, page *TPage) []*TPage {
+	return append([]*TPage{page}, withoutPage(mru, page)...)
+}
+
+// withoutPage 返回删除页后的列表, 页不在列表中时返回原列表
+func withoutPage(pages []*TPage, page *TPage) []*TPage {
+	for i, p := range pages {
+		if p == page {
+			return append(pages[:i:i], pages[i+1:]...)
+		}
+	}
+	return pages
+}
+
+// mruOrder 返回按最近使用列表 mru 排列的显示的页 visible, 不在列表中的页按原顺序排在最后
+func mruOrder(mru, visible []*TPage) []*TPage {
+	var pages []*TPage
+	for _, page := range mru {
+		for _, p := range visible {
+			if p == page {
+				pages = append(pages, page)
+				break
+			}
+		}
+	}
+	for _, page := range visible {
+		found := false
+		for _, p := range pages {
+			if p == page {
+				found = true
+				break
+			}
+		}
+		if !found {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// tTabCycle 按住修饰键连续切换页, 只在主线程访问
+type tTabCycle struct {
+	pages []*TPage // 开始切换时的页顺序
+	index int
+	form  lcl.IForm    // 切换窗口, 不显示时为 nil
+	list  lcl.IListBox //
+}
+
+// newTabCycle 从当前页开始切换 pages, 没有当前页时从第一页开始, 少于 2 页时返回 nil
+func newTabCycle(pages []*TPage) *tTabCycle {
+	if len(pages) < 2 {
+		return nil
+	}
+	index := 0
+	for i, page := range pages {
+		if page.Active() {
+			index = i
+		}
+	}
+	return &tTabCycle{pages: pages, index: index}
+}
+
+// step 切换 step 页, 首尾循环, 返回切换到的页
+func (c *tTabCycle) step(step int) *TPage {
+	c.index = (c.index + step + len(c.pages)) % len(c.pages)
+	return c.pages[c.index]
+}
+
+// cyclePage 切换到前后的页, hold 为 true 时连续切换直到松开修饰键, 在主线程执行
+//
+//	没有切换窗口时立即激活页, 连续切换中不更新最近使用顺序
+func (m *TTab) cyclePage(step int, hold bool) {
+	if m.cycle == nil {
+		pages := m.visiblePages()
+		if m.MRUOrder() {
+			pages = m.mruPages()
+		}
+		if m.cycle = newTabCycle(pages); m.cycle == nil {
+			return
+		}
+		if hold && m.Switcher() {
+			m.showSwitcher()
+		}
+	}
+	cycle := m.cycle
+	page := cycle.step(step)
+	if cycle.list != nil {
+		cycle.list.SetItemIndex(int32(cycle.index))
+	} else {
+		m.selectPage(page)
+	}
+	if !hold {
+		m.endCycle(true)
+	}
+}
+
+// endCycle 结束连续切换, commit 为 true 时激活选择的页并更新最近使用顺序, 在主线程执行
+func (m *TTab) endCycle(commit bool) {
+	cycle := m.cycle
+	if cycle == nil {
+		return
+	}
+	m.cycle = nil
+	if cycle.form != nil && cycle.form.IsValid() {
+		cycle.form.Close()
+	}
+	page := cycle.pages[cycle.index]
+	if !commit || page.tab != m || !page.IsValid() {
+		return
+	}
+	if cycle.form != nil {
+		m.selectPage(page)
+	}
+	m.touchMRU(page)
+}
+
+// showSwitcher 在 TTab 中央打开切换窗口, 在主线程执行
+func (m *TTab) showSwitcher() {
+	cycle := m.cycle
+	form := lcl.NewForm(nil)
+	form.SetBorderStyleToFormBorderStyle(types.BsNoneForm)
+	form.SetFormStyle(types.FsStayOnTop)
+	form.SetShowInTaskBar(types.StNever)
+	form.SetKeyPreview(true)
+	cycle.form = form
+
+	list := lcl.NewListBox(form)
+	list.SetParent(form)
+	list.SetAlign(types.AlClient)
+	list.SetBorderStyle(types.BsNone)
+	list.SetStyle(types.LbOwnerDrawFixed)
+	list.SetItemHeight(overflowItemHeight)
+	cycle.list = list
+	items := list.Items()
+	items.BeginUpdate()
+	for _, page := range cycle.pages {
+		items.Add(page.button.Caption())
+	}
+	items.EndUpdate()
+
+	list.SetOnDrawItem(func(control lcl.IWinControl, index int32, aRect types.TRect, state types.TOwnerDrawState) {
+		if index < 0 || int(index) >= len(cycle.pages) {
+			return
+		}
+		m.drawOverflowItem(list.Canvas(), cycle.pages[index], aRect, state.In(types.OdSelected))
+	})
+	list.SetOnClick(func(sender lcl.IObject) {
+		if index := int(list.ItemIndex()); m.cycle == cycle && index >= 0 && index < len(cycle.pages) {
+			cycle.index = index
+			m.endCycle(true)
+		}
+	})
+	// 切换窗口获得焦点, 按住修饰键期间的按键都由切换窗口处理
+	form.SetOnKeyDown(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		if m.cycle != cycle {
+			return
+		}
+		command, ok := m.commandOf(*key, shift)
+		switch {
+		case ok && command == TcCycleNext, *key == keys.VkDown:
+			m.cyclePage(1, true)
+		case ok && command == TcCyclePrev, *key == keys.VkUp:
+			m.cyclePage(-1, true)
+		case *key == keys.VkReturn:
+			m.endCycle(true)
+		case *key == keys.VkEscape:
+			m.endCycle(false)
+		}
+		*key = 0
+	})
+	form.SetOnKeyUp(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		if m.cycle == cycle && !holding(shift) {
+			m.endCycle(true)
+		}
+	})
+	form.SetOnDeactivate(func(sender lcl.IObject) {
+		// 不能在失去焦点的事件中释放窗口
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if m.cycle == cycle {
+				m.endCycle(false)
+			}
+		})
+	})
+	form.SetOnClose(func(sender lcl.IObject, closeAction *types.TCloseAction) {
+		*closeAction = types.CaFree
+	})
+
+	rows := len(cycle.pages)
+	if rows > switcherMaxItems {
+		rows = switcherMaxItems
+	}
+	width, height := switcherWidth, int32(rows)*overflowItemHeight+2
+	pos := m.ClientToScreen(types.TPoint{X: (m.Width() - width) / 2, Y: (m.Height() - height) / 2})
+	form.SetBounds(pos.X, pos.Y, width, height)
+	form.Show()
+	list.SetFocus()
+}
@@ -0,0 +1,159 @@
+package wg
+
+import (
+	"testing"
+
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/keys"
+)
+
+func TestTabShortcutMatch(t *testing.T) {
+	ctrlTab := NewTabShortcut(keys.VkTab, types.SsCtrl)
+	tests := []struct {
+		name  string
+		key   uint16
+		shift types.TShiftState
+		want  bool
+	}{
+		{"same", keys.VkTab, types.NewSet(types.SsCtrl), true},
+		{"extra shift", keys.VkTab, types.NewSet(types.SsCtrl, types.SsShift), false},
+		{"extra alt", keys.VkTab, types.NewSet(types.SsCtrl, types.SsAlt), false},
+		{"missing ctrl", keys.VkTab, 0, false},
+		{"other key", keys.VkW, types.NewSet(types.SsCtrl), false},
+		// 鼠标按键等其它状态不比较
+		{"mouse button", keys.VkTab, types.NewSet(types.SsCtrl, types.SsLeft), true},
+	}
+	for _, tt := range tests {
+		if got := ctrlTab.match(tt.key, tt.shift); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultTabShortcuts(t *testing.T) {
+	m := &TTab{shortcuts: defaultTabShortcuts()}
+	ctrl := types.NewSet(types.SsCtrl)
+	tests := []struct {
+		name  string
+		key   uint16
+		shift types.TShiftState
+		want  TTabCommand
+		ok    bool
+	}{
+		{"Ctrl+Tab", keys.VkTab, ctrl, TcCycleNext, true},
+		{"Ctrl+Shift+Tab", keys.VkTab, types.NewSet(types.SsCtrl, types.SsShift), TcCyclePrev, true},
+		{"Ctrl+PageDown", keys.VkNext, ctrl, TcNextPage, true},
+		{"Ctrl+PageUp", keys.VkPrior, ctrl, TcPrevPage, true},
+		{"Ctrl+W", keys.VkW, ctrl, TcClosePage, true},
+		{"Ctrl+F4", keys.VkF4, ctrl, TcClosePage, true},
+		{"Ctrl+9", keys.Vk9, ctrl, TcLastPage, true},
+		{"Left", keys.VkLeft, 0, TcStripPrev, true},
+		{"Down", keys.VkDown, 0, TcStripNext, true},
+		{"Home", keys.VkHome, 0, TcStripFirst, true},
+		{"End", keys.VkEnd, 0, TcStripLast, true},
+		{"Tab", keys.VkTab, 0, 0, false},
+		{"Ctrl+Alt+1", keys.Vk1, types.NewSet(types.SsCtrl, types.SsAlt), 0, false},
+		{"Shift+Left", keys.VkLeft, types.NewSet(types.SsShift), 0, false},
+	}
+	for i := 0; i < 8; i++ {
+		tests = append(tests, struct {
+			name  string
+			key   uint16
+			shift types.TShiftState
+			want  TTabCommand
+			ok    bool
+		}{"Ctrl+digit", keys.Vk1 + uint16(i), ctrl, TcPage1 + TTabCommand(i), true})
+	}
+	for _, tt := range tests {
+		got, ok := m.commandOf(tt.key, tt.shift)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("%s (key %d): command = %d, %v, want %d, %v", tt.name, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+	// Ctrl+Shift+Tab 不匹配 TcCycleNext 的快捷键
+	for _, shortcut := range m.shortcuts[TcCycleNext] {
+		if shortcut.match(keys.VkTab, types.NewSet(types.SsCtrl, types.SsShift)) {
+			t.Error("Ctrl+Shift+Tab matches TcCycleNext")
+		}
+	}
+}
+
+func TestMRUOrder(t *testing.T) {
+	a, b, c, d := &TPage{}, &TPage{}, &TPage{}, &TPage{}
+	tests := []struct {
+		name         string
+		mru, visible []*TPage
+		want         []*TPage
+	}{
+		{"no history", nil, []*TPage{a, b, c}, []*TPage{a, b, c}},
+		{"history first", []*TPage{c, a}, []*TPage{a, b, c, d}, []*TPage{c, a, b, d}},
+		{"skip hidden", []*TPage{c, b, a}, []*TPage{a, c}, []*TPage{c, a}},
+	}
+	for _, tt := range tests {
+		if got := mruOrder(tt.mru, tt.visible); !samePages(got, tt.want) {
+			t.Errorf("%s: wrong order", tt.name)
+		}
+	}
+	mru := touchPage(touchPage(touchPage(nil, a), b), a)
+	if !samePages(mru, []*TPage{a, b}) {
+		t.Error("touchPage did not move the page to the front")
+	}
+	if mru = withoutPage(mru, a); !samePages(mru, []*TPage{b}) {
+		t.Error("withoutPage did not remove the page")
+	}
+	if mru = withoutPage(mru, c); !samePages(mru, []*TPage{b}) {
+		t.Error("withoutPage changed the list for a missing page")
+	}
+}
+
+// cycleMRU 模拟一次 cyclePage 到 endCycle: 按最近使用顺序切换 steps, 松开后更新最近使用列表, 返回切换到的页
+func cycleMRU(mru, visible []*TPage, steps ...int) ([]*TPage, *TPage) {
+	cycle := newTabCycle(mruOrder(mru, visible))
+	if cycle == nil {
+		return mru, nil
+	}
+	var page *TPage
+	for _, step := range steps {
+		page = cycle.step(step)
+	}
+	for _, p := range visible {
+		p.active = p == page
+	}
+	return touchPage(mru, page), page
+}
+
+func TestCycleMRU(t *testing.T) {
+	a, b, c, d := &TPage{active: true}, &TPage{}, &TPage{}, &TPage{}
+	visible := []*TPage{a, b, c, d}
+	mru := []*TPage{a}
+	tests := []struct {
+		name    string
+		steps   []int
+		want    *TPage
+		wantMRU []*TPage
+	}{
+		{"Ctrl+Tab", []int{1}, b, []*TPage{b, a}},
+		{"Ctrl+Tab back", []int{1}, a, []*TPage{a, b}},
+		{"hold Ctrl, Tab three times", []int{1, 1, 1}, d, []*TPage{d, a, b}},
+		{"Ctrl+Shift+Tab wraps", []int{-1}, c, []*TPage{c, d, a, b}},
+		{"hold Ctrl, Tab, Shift+Tab", []int{1, 1, -1}, d, []*TPage{d, c, a, b}},
+	}
+	for _, tt := range tests {
+		var page *TPage
+		mru, page = cycleMRU(mru, visible, tt.steps...)
+		if page != tt.want || !samePages(mru, tt.wantMRU) {
+			t.Errorf("%s: switched to %d, mru %v", tt.name, pageIndex(visible, page), mru)
+		}
+	}
+	// 少于 2 页时不切换
+	if cycle := newTabCycle([]*TPage{a}); cycle != nil {
+		t.Error("cycle with one page")
+	}
+	// 没有当前页时从第一页开始
+	for _, p := range visible {
+		p.active = false
+	}
+	if page := newTabCycle(visible).step(1); page != b {
+		t.Errorf("without active page switched to %d, want 1", pageIndex(visible, page))
+	}
+}
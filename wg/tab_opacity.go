@@ -0,0 +1,58 @@
+package wg
+
+import "time"
+
+// SetOpacity 设置页签栏的整体不透明度 0 ~ 255, 作用于页签, 组标签, 滚动导航按钮和页签列表按钮
+//
+//	页的内容是窗口控件, 不受影响; 会停止正在进行的渐变动画
+func (m *TTab) SetOpacity(opacity byte) {
+	m.fadeSeq.Add(1)
+	m.setOpacity(opacity)
+}
+
+// Opacity 返回页签栏的整体不透明度
+func (m *TTab) Opacity() byte {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.opacity
+}
+
+// setOpacity 设置页签栏所有按钮的不透明度, 不影响渐变动画
+func (m *TTab) setOpacity(opacity byte) {
+	m.lock.Lock()
+	m.opacity = opacity
+	m.lock.Unlock()
+	for _, button := range m.stripButtons() {
+		button.setOpacity(opacity)
+	}
+}
+
+// stripButtons 返回页签栏中的所有按钮
+func (m *TTab) stripButtons() []*TButton {
+	buttons := []*TButton{m.scrollLeftBtn, m.scrollRightBtn, m.overflowBtn}
+	for _, page := range m.Pages() {
+		buttons = append(buttons, page.button)
+	}
+	for _, group := range m.Groups() {
+		buttons = append(buttons, group.chip)
+	}
+	return buttons
+}
+
+// FadeIn 显示页签栏的按钮, 在 duration 内不透明度从 0 变到 255, 结束后调用 done(可以为 nil)
+//
+//	被新的渐变或 SetOpacity 打断时也调用 done, 不透明度保持打断时的值
+func (m *TTab) FadeIn(duration time.Duration, done func()) {
+	seq := m.fadeSeq.Add(1)
+	m.setOpacity(0)
+	animateOpacity(&m.fadeSeq, seq, 0, 255, duration, m.setOpacity, fadeDone(done))
+}
+
+// FadeOut 在 duration 内页签栏按钮的不透明度变到 0, 结束后调用 done(可以为 nil)
+//
+//	TTab 包含页的内容, 结束后不隐藏, 需要时在 done 中隐藏并恢复不透明度
+//	被新的渐变或 SetOpacity 打断时也调用 done, 不透明度保持打断时的值
+func (m *TTab) FadeOut(duration time.Duration, done func()) {
+	seq := m.fadeSeq.Add(1)
+	animateOpacity(&m.fadeSeq, seq, m.Opacity(), 0, duration, m.setOpacity, fadeDone(done))
+}
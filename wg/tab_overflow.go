@@ -0,0 +1,284 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"github.com/energye/lcl/types/keys"
+	"strings"
+	"time"
+)
+
+// 页签列表下拉框
+var (
+	overflowListWidth    = int32(240)
+	overflowItemHeight   = int32(24)
+	overflowItemPadding  = int32(6)
+	overflowMaxItems     = 12
+	overflowMarkerColor  = colors.RGBToColor(0, 120, 215)
+	overflowHiddenColor  = colors.ClGray
+	overflowReopenIgnore = time.Second / 5 // 点击按钮使列表失去焦点关闭后, 同一次点击不再打开
+)
+
+// SetOverflowButton 设置是否在页签栏末尾显示页签列表按钮, 点击后列出所有页签, 默认不显示
+func (m *TTab) SetOverflowButton(enabled bool) {
+	runOnMainThread(func() {
+		if m.overflowBtn == nil || !m.IsValid() {
+			return
+		}
+		m.overflowBtn.SetVisible(enabled)
+		m.recalculatePosition()
+	})
+}
+
+// OverflowButton 返回页签列表按钮, 可以设置图标, 颜色和提示
+func (m *TTab) OverflowButton() *TButton {
+	return m.overflowBtn
+}
+
+// SetOverflowShowHidden 设置页签列表是否包含隐藏的页签, 默认不包含
+//
+//	选择隐藏的页签时先显示该页签
+func (m *TTab) SetOverflowShowHidden(show bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.overflowShowHidden = show
+}
+
+// OverflowShowHidden 返回页签列表是否包含隐藏的页签
+func (m *TTab) OverflowShowHidden() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.overflowShowHidden
+}
+
+// ShowOverflowList 在页签列表按钮下方打开页签列表, 已打开时关闭
+//
+//	输入文字按标题过滤, 上下键选择, 回车或单击激活页签并滚动到可见位置, Esc 或失去焦点时关闭
+func (m *TTab) ShowOverflowList() {
+	runOnMainThread(m.showOverflowList)
+}
+
+// initOverflowBtn 初始化页签列表按钮, 默认隐藏
+func (m *TTab) initOverflowBtn() {
+	m.overflowBtn = NewButton(m)
+	m.overflowBtn.SetIconAsset("tab/overflow")
+	m.overflowBtn.SetWidth(scrollBtnWidth)
+	m.overflowBtn.SetHeight(scrollBtnHeight)
+	m.overflowBtn.SetRadius(1)
+	m.overflowBtn.SetBorderDirections(types.NewSet())
+	m.overflowBtn.SetColor(LightenColor(colors.ClGray, 0.2))
+	m.overflowBtn.SetVisible(false)
+	m.overflowBtn.SetParent(m)
+	m.overflowBtn.SetOnClick(func(sender lcl.IObject) {
+		m.showOverflowList()
+	})
+}
+
+// overflowWidth 返回页签列表按钮占用的宽度, 不显示时为 0
+func (m *TTab) overflowWidth() int32 {
+	if m.overflowBtn != nil && m.overflowBtn.Visible() {
+		return scrollBtnWidth + scrollBtnMargin
+	}
+	return 0
+}
+
+// overflowPages 返回页签列表中的页
+func (m *TTab) overflowPages() []*TPage {
+	showHidden := m.OverflowShowHidden()
+	var pages []*TPage
+	for _, page := range m.Pages() {
+		if showHidden || page.button.Visible() {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// showOverflowList 打开或关闭页签列表, 在主线程执行
+func (m *TTab) showOverflowList() {
+	if m.overflowForm != nil {
+		m.overflowForm.Close()
+		return
+	}
+	if time.Since(m.overflowClosed) < overflowReopenIgnore {
+		return
+	}
+	all := m.overflowPages()
+	if len(all) == 0 || !m.IsValid() {
+		return
+	}
+
+	form := lcl.NewForm(nil)
+	form.SetBorderStyleToFormBorderStyle(types.BsNoneForm)
+	form.SetFormStyle(types.FsStayOnTop)
+	form.SetShowInTaskBar(types.StNever)
+	form.SetKeyPreview(true)
+	m.overflowForm = form
+
+	edit := lcl.NewEdit(form)
+	edit.SetParent(form)
+	edit.SetAlign(types.AlTop)
+
+	list := lcl.NewListBox(form)
+	list.SetParent(form)
+	list.SetAlign(types.AlClient)
+	list.SetBorderStyle(types.BsNone)
+	list.SetStyle(types.LbOwnerDrawFixed)
+	list.SetItemHeight(overflowItemHeight)
+	list.SetTabStop(false)
+
+	// 过滤后显示的页, 和列表项一一对应
+	var shown []*TPage
+	filter := func() {
+		text := strings.ToLower(strings.TrimSpace(edit.Text()))
+		shown = shown[:0]
+		selected := int32(0)
+		items := list.Items()
+		items.BeginUpdate()
+		items.Clear()
+		for _, page := range all {
+			caption := page.button.Caption()
+			if text != "" && !strings.Contains(strings.ToLower(caption), text) {
+				continue
+			}
+			if page.Active() {
+				selected = int32(len(shown))
+			}
+			shown = append(shown, page)
+			items.Add(caption)
+		}
+		items.EndUpdate()
+		if len(shown) > 0 {
+			list.SetItemIndex(selected)
+		}
+	}
+	choose := func() {
+		index := list.ItemIndex()
+		if index < 0 || int(index) >= len(shown) {
+			return
+		}
+		page := shown[index]
+		form.Close()
+		m.selectPage(page)
+	}
+
+	edit.SetOnChange(func(sender lcl.IObject) {
+		filter()
+	})
+	list.SetOnClick(func(sender lcl.IObject) {
+		choose()
+	})
+	list.SetOnDrawItem(func(control lcl.IWinControl, index int32, aRect types.TRect, state types.TOwnerDrawState) {
+		if index < 0 || int(index) >= len(shown) {
+			return
+		}
+		m.drawOverflowItem(list.Canvas(), shown[index], aRect, state.In(types.OdSelected))
+	})
+	// 焦点在输入框中, 按键由窗口预先处理
+	form.SetOnKeyDown(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		count := int32(len(shown))
+		switch *key {
+		case keys.VkUp:
+			if count > 0 {
+				list.SetItemIndex((list.ItemIndex() - 1 + count) % count)
+			}
+		case keys.VkDown:
+			if count > 0 {
+				list.SetItemIndex((list.ItemIndex() + 1) % count)
+			}
+		case keys.VkReturn:
+			choose()
+		case keys.VkEscape:
+			form.Close()
+		default:
+			return
+		}
+		*key = 0
+	})
+	form.SetOnDeactivate(func(sender lcl.IObject) {
+		// 不能在失去焦点的事件中释放窗口
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			if form.IsValid() {
+				form.Close()
+			}
+		})
+	})
+	form.SetOnClose(func(sender lcl.IObject, closeAction *types.TCloseAction) {
+		*closeAction = types.CaFree
+		if m.overflowForm == form {
+			m.overflowForm = nil
+			m.overflowClosed = time.Now()
+		}
+	})
+
+	filter()
+	rows := len(all)
+	if rows > overflowMaxItems {
+		rows = overflowMaxItems
+	}
+	// 在页签列表按钮靠内容区的一侧打开, 横向右对齐, 纵向上对齐
+	button := m.overflowBtn
+	height := edit.Height() + int32(rows)*overflowItemHeight + 2
+	pos := m.ClientToScreen(types.TPoint{X: button.Left(), Y: button.Top()})
+	switch m.position {
+	case TpBottom:
+		pos.X, pos.Y = pos.X+button.Width()-overflowListWidth, pos.Y-height
+	case TpLeft:
+		pos.X += button.Width()
+	case TpRight:
+		pos.X -= overflowListWidth
+	default:
+		pos.X, pos.Y = pos.X+button.Width()-overflowListWidth, pos.Y+button.Height()
+	}
+	form.SetBounds(pos.X, pos.Y, overflowListWidth, height)
+	form.Show()
+	edit.SetFocus()
+}
+
+// drawOverflowItem 绘制页签列表项: 当前页标记, 前置图标, 标题, 隐藏的页签标题为灰色
+func (m *TTab) drawOverflowItem(canvas lcl.ICanvas, page *TPage, rect types.TRect, selected bool) {
+	brush := canvas.BrushToBrush()
+	brush.SetStyle(types.BsSolid)
+	if selected {
+		brush.SetColor(activeColor)
+	} else {
+		brush.SetColor(defaultColor)
+	}
+	canvas.FillRectWithRect(rect)
+	if page.Active() {
+		brush.SetColor(overflowMarkerColor)
+		canvas.FillRectWithIntX4(rect.Left, rect.Top+2, rect.Left+3, rect.Bottom-2)
+	}
+	x := rect.Left + overflowItemPadding
+	if icon := page.button.iconFavorite; icon.Width() > 0 {
+		canvas.DrawWithIntX2Graphic(x, rect.Top+(rect.Height()-icon.Height())/2, icon.Graphic())
+		x += icon.Width() + overflowItemPadding
+	}
+	caption := page.button.Caption()
+	font := canvas.FontToFont()
+	if page.button.Visible() {
+		font.SetColor(colors.Cl3DFace)
+	} else {
+		font.SetColor(overflowHiddenColor)
+	}
+	brush.SetStyle(types.BsClear)
+	canvas.TextOutWithIntX2Str(x, rect.Top+(rect.Height()-canvas.GetTextHeight(caption))/2, caption)
+}
+
+// selectPage 激活页并滚动到可见位置, 折叠的组先展开, 隐藏的页先显示, 在主线程执行
+func (m *TTab) selectPage(page *TPage) {
+	if page.tab != m || !page.IsValid() {
+		return
+	}
+	if group := page.Group(); group != nil && group.Collapsed() {
+		group.SetCollapsed(false)
+	}
+	m.HideAllActivated()
+	if !page.button.Visible() {
+		page.Show()
+	} else {
+		page.SetActive(true)
+	}
+	m.scrollIntoView(page)
+}
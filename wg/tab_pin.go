@@ -0,0 +1,157 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+)
+
+// 固定的页签沿排列方向的长度
+var pinnedTabLength = int32(36)
+
+// TPinEvent 页签固定状态变化事件
+type TPinEvent func(sender lcl.IObject, page *TPage, pinned bool)
+
+// SetOnPinChange 设置页签固定或取消固定后的事件, sender 为 TTab
+func (m *TTab) SetOnPinChange(fn TPinEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onPinChange = fn
+}
+
+// CloseOthers 关闭 page 以外的页并激活 page, 固定的页签不关闭, page 为 nil 时关闭所有没有固定的页
+func (m *TTab) CloseOthers(page *TPage) {
+	runOnMainThread(func() {
+		// 先激活保留的页, 关闭其它页时不再切换当前页
+		if page != nil && page.tab == m {
+			m.selectPage(page)
+		}
+		for _, p := range m.Pages() {
+			if p != page && !p.Pinned() {
+				p.Close()
+			}
+		}
+	})
+}
+
+// Pin 固定页签
+//
+//	固定的页签移到页签栏起点, 只显示前置图标, 长度固定, 不显示关闭图标, 滚动时一直可见,
+//	CloseOthers 不关闭; 没有前置图标时显示标题的第一个字
+func (m *TPage) Pin() {
+	m.setPinned(true)
+}
+
+// Unpin 取消固定, 页签移到固定的页签之后, 恢复固定前的长度
+func (m *TPage) Unpin() {
+	m.setPinned(false)
+}
+
+// Pinned 返回页签是否固定
+func (m *TPage) Pinned() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.pinned
+}
+
+// setPinned 设置页签是否固定, 状态变化时触发 OnPinChange
+func (m *TPage) setPinned(pinned bool) {
+	runOnMainThread(func() {
+		tab := m.tab
+		if tab == nil || !m.IsValid() {
+			return
+		}
+		button := m.button
+		m.lock.Lock()
+		changed := m.pinned != pinned
+		m.pinned = pinned
+		if changed && pinned {
+			// 记录沿文字方向的长度, 取消固定时恢复
+			m.unpinnedLength = button.Width()
+			if button.TextRotation() != TrNone {
+				m.unpinnedLength = button.Height()
+			}
+		}
+		length := m.unpinnedLength
+		m.lock.Unlock()
+		if !changed {
+			return
+		}
+		button.lock.Lock()
+		button.iconOnly = pinned
+		button.lock.Unlock()
+		if !pinned {
+			if button.TextRotation() != TrNone {
+				button.SetHeight(length)
+			} else {
+				button.SetWidth(length)
+			}
+		}
+		if pinned {
+			tab.setPageGroup(m, nil)
+		}
+		tab.applyButtonPosition(button)
+		tab.placePinned(m)
+		tab.recalculatePosition()
+		if m.Active() {
+			tab.scrollToActiveLater(m)
+		}
+		tab.lock.RLock()
+		fn := tab.onPinChange
+		tab.lock.RUnlock()
+		if fn != nil {
+			fn(tab, m, pinned)
+		}
+	})
+}
+
+// placePinned 把页移到固定页签和其它页签的分界处, 在主线程执行
+func (m *TTab) placePinned(page *TPage) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	pages := make([]*TPage, 0, len(m.pages))
+	for _, p := range m.pages {
+		if p != page {
+			pages = append(pages, p)
+		}
+	}
+	index := m.pinnedCount(pages)
+	m.pages = append(pages[:index:index], append([]*TPage{page}, pages[index:]...)...)
+}
+
+// pinnedCount 返回页列表开头固定页签的数量
+func (m *TTab) pinnedCount(pages []*TPage) int {
+	count := 0
+	for count < len(pages) && pages[count].Pinned() {
+		count++
+	}
+	return count
+}
+
+// isIconOnly 返回按钮是否只显示前置图标
+func (m *TButton) isIconOnly() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.iconOnly
+}
+
+// drawIconOnlyContent 在按钮中央绘制前置图标, 没有前置图标时绘制文字的第一个字
+func (m *TButton) drawIconOnlyContent(canvas lcl.ICanvas, rect types.TRect, text string, favoriteGlyph *TIconGlyph, state TButtonState) {
+	textColor := canvas.FontToFont().Color()
+	if fr, ok := m.favoriteRect(rect); ok {
+		if m.iconFavorite.Width() > 0 {
+			canvas.DrawWithIntX2Graphic(fr.Left, fr.Top, m.iconFavorite.Graphic())
+		} else if favoriteGlyph != nil {
+			drawGlyph(canvas, favoriteGlyph, fr.Left, fr.Top, m.glyphColor(favoriteGlyph, textColor, state, colors.ClNone))
+		} else if images, index := m.actionImages(); images != nil {
+			images.Draw(canvas, fr.Left, fr.Top, index, !m.Disable())
+		}
+		return
+	}
+	for _, r := range text {
+		letter := string(r)
+		size := canvas.TextExtentWithStr(letter)
+		canvas.TextOutWithIntX2Str(rect.Left+(rect.Width()-size.Cx)/2, rect.Top+(rect.Height()-size.Cy)/2, letter)
+		return
+	}
+}
@@ -0,0 +1,344 @@
+package wg
+
+import (
+	"github.com/energye/lcl/types"
+)
+
+// 纵向页签栏横排文字时的默认宽度
+var verticalStripWidth = int32(120)
+
+// TTabPosition 页签栏位置
+type TTabPosition int32
+
+const (
+	TpTop    TTabPosition = iota // 上, 默认
+	TpBottom                     // 下
+	TpLeft                       // 左, 页签纵向排列
+	TpRight                      // 右, 页签纵向排列
+)
+
+// SetTabPosition 设置页签栏位置, 运行时修改会重新排列页签和内容区
+//
+//	页签的圆角和边框随位置镜像, 纵向时滚动导航按钮变为上下滚动
+func (m *TTab) SetTabPosition(position TTabPosition) {
+	runOnMainThread(func() {
+		m.lock.Lock()
+		changed := m.position != position
+		m.position = position
+		m.lock.Unlock()
+		if changed {
+			m.applyPosition()
+		}
+	})
+}
+
+// TabPosition 返回页签栏位置
+func (m *TTab) TabPosition() TTabPosition {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.position
+}
+
+// SetRotatedText 设置纵向页签栏是否旋转文字, 左侧从下向上阅读, 右侧从上向下阅读, 默认横排
+//
+//	横排时页签宽度固定为 VerticalStripWidth, 页签按钮的自动大小不起作用
+func (m *TTab) SetRotatedText(rotated bool) {
+	runOnMainThread(func() {
+		m.lock.Lock()
+		changed := m.rotatedText != rotated
+		m.rotatedText = rotated
+		m.lock.Unlock()
+		if changed {
+			m.applyPosition()
+		}
+	})
+}
+
+// RotatedText 返回纵向页签栏是否旋转文字
+func (m *TTab) RotatedText() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.rotatedText
+}
+
+// SetVerticalStripWidth 设置纵向页签栏横排文字时的宽度
+func (m *TTab) SetVerticalStripWidth(width int32) {
+	runOnMainThread(func() {
+		m.lock.Lock()
+		m.stripWidth = width
+		m.lock.Unlock()
+		if m.vertical() && !m.rotatedText {
+			m.applyPosition()
+		}
+	})
+}
+
+// VerticalStripWidth 返回纵向页签栏横排文字时的宽度
+func (m *TTab) VerticalStripWidth() int32 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.stripWidth
+}
+
+// 以下方法读取的 position, rotatedText 只在主线程修改, 在主线程执行
+
+// vertical 返回页签是否纵向排列
+func (m *TTab) vertical() bool {
+	return m.position == TpLeft || m.position == TpRight
+}
+
+// btnPos 返回按钮在排列方向上的位置
+func (m *TTab) btnPos(button *TButton) int32 {
+	if m.vertical() {
+		return button.Top()
+	}
+	return button.Left()
+}
+
+// setBtnPos 设置按钮在排列方向上的位置
+func (m *TTab) setBtnPos(button *TButton, pos int32) {
+	if m.vertical() {
+		button.SetTop(pos)
+	} else {
+		button.SetLeft(pos)
+	}
+}
+
+// btnLen 返回按钮在排列方向上的长度
+func (m *TTab) btnLen(button *TButton) int32 {
+	if m.vertical() {
+		return button.Height()
+	}
+	return button.Width()
+}
+
+// placeCross 设置按钮在页签栏厚度方向上的位置, 靠内容区一侧对齐
+func (m *TTab) placeCross(button *TButton) {
+	switch m.position {
+	case TpBottom:
+		button.SetTop(m.Height() - m.stripThickness())
+	case TpLeft:
+		button.SetLeft(m.stripThickness() - button.Width())
+	case TpRight:
+		button.SetLeft(m.Width() - m.stripThickness())
+	default:
+		button.SetTop(m.stripThickness() - button.Height())
+	}
+}
+
+// mainCoord 把 TTab 坐标转换为排列方向和垂直方向的坐标
+func (m *TTab) mainCoord(x, y int32) (main, cross int32) {
+	if m.vertical() {
+		return y, x
+	}
+	return x, y
+}
+
+// stripLength 返回页签栏在排列方向上的长度
+func (m *TTab) stripLength() int32 {
+	if m.vertical() {
+		return m.Height()
+	}
+	return m.Width()
+}
+
+// stripThickness 返回页签栏的厚度, 横向为高度, 纵向为宽度, 多行时为所有行的厚度
+func (m *TTab) stripThickness() int32 {
+	return m.rowThickness() * int32(m.RowCount())
+}
+
+// rowThickness 返回一行页签的厚度
+func (m *TTab) rowThickness() int32 {
+	vertical := m.vertical()
+	for _, page := range m.Pages() {
+		if page.button.Visible() {
+			if vertical {
+				return page.button.Width()
+			}
+			return page.button.Height()
+		}
+	}
+	return m.buttonThickness()
+}
+
+// buttonThickness 返回按当前位置设置的页签按钮厚度
+func (m *TTab) buttonThickness() int32 {
+	if m.vertical() && !m.rotatedText {
+		return m.VerticalStripWidth()
+	}
+	return defaultHeight
+}
+
+// stripRect 返回页签栏区域, TTab 坐标
+func (m *TTab) stripRect() types.TRect {
+	w, h, t := m.Width(), m.Height(), m.stripThickness()
+	switch m.position {
+	case TpBottom:
+		return types.TRect{Top: h - t, Right: w, Bottom: h}
+	case TpLeft:
+		return types.TRect{Right: t, Bottom: h}
+	case TpRight:
+		return types.TRect{Left: w - t, Right: w, Bottom: h}
+	}
+	return types.TRect{Right: w, Bottom: t}
+}
+
+// stripStart 返回滚动页签区域在排列方向上的起点, 固定的页签和左(上)滚动按钮之后
+func (m *TTab) stripStart() int32 {
+	if m.scrollLeftBtn != nil && m.scrollLeftBtn.Visible() {
+		return m.pinnedWidth + scrollBtnWidth + scrollBtnMargin
+	}
+	return m.pinnedWidth
+}
+
+// stripEnd 返回页签区域在排列方向上的终点, 右(下)滚动按钮和页签列表按钮之前
+func (m *TTab) stripEnd() int32 {
+	end := m.stripLength() - m.overflowWidth()
+	if m.scrollRightBtn != nil && m.scrollRightBtn.Visible() {
+		end -= scrollBtnWidth + scrollBtnMargin
+	}
+	return end
+}
+
+// layoutSheet 设置页内容区位置, 填满 TTab 中页签栏以外的区域
+func (m *TTab) layoutSheet(page *TPage) {
+	w, h, t := m.ClientWidth(), m.ClientHeight(), m.stripThickness()
+	sheet := page.ICustomPanel
+	switch m.position {
+	case TpBottom:
+		sheet.SetBounds(0, 0, w, h-t)
+	case TpLeft:
+		sheet.SetBounds(t, 0, w-t, h)
+	case TpRight:
+		sheet.SetBounds(0, 0, w-t, h)
+	default:
+		sheet.SetBounds(0, t, w, h-t)
+	}
+}
+
+// applyButtonPosition 按页签栏位置设置页签按钮的大小, 文字方向, 圆角和边框
+//
+//	保留按钮沿文字方向的长度, 纵向横排文字时宽度为 VerticalStripWidth, 固定的页签长度固定
+func (m *TTab) applyButtonPosition(button *TButton) {
+	length, thickness := button.Width(), button.Height()
+	if button.TextRotation() != TrNone {
+		length, thickness = thickness, length
+	}
+	rotation := TrNone
+	corners := types.NewSet(RcLeftTop, RcRightTop, RcLeftBottom, RcRightBottom)
+	borders := types.NewSet(BbdLeft, BbdTop, BbdRight, BbdBottom)
+	// 靠内容区一侧没有圆角和边框
+	switch m.position {
+	case TpBottom:
+		corners = corners.Exclude(RcLeftTop, RcRightTop)
+		borders = borders.Exclude(BbdTop)
+	case TpLeft:
+		corners = corners.Exclude(RcRightTop, RcRightBottom)
+		borders = borders.Exclude(BbdRight)
+		rotation = TrUp
+	case TpRight:
+		corners = corners.Exclude(RcLeftTop, RcLeftBottom)
+		borders = borders.Exclude(BbdLeft)
+		rotation = TrDown
+	default:
+		corners = corners.Exclude(RcLeftBottom, RcRightBottom)
+		borders = borders.Exclude(BbdBottom)
+	}
+	button.lock.Lock()
+	iconOnly := button.iconOnly
+	button.fixedSize = iconOnly || m.vertical() && !m.rotatedText
+	button.lock.Unlock()
+	if m.vertical() && !m.rotatedText {
+		rotation = TrNone
+		length, thickness = m.VerticalStripWidth(), defaultHeight
+	} else if iconOnly {
+		// 固定的页签只显示图标
+		length = pinnedTabLength
+	}
+	button.SetRoundedCorners(corners)
+	button.SetBorderDirections(borders)
+	button.SetTextRotation(rotation)
+	if rotation == TrNone {
+		button.SetBounds(button.Left(), button.Top(), length, thickness)
+	} else {
+		button.SetBounds(button.Left(), button.Top(), thickness, length)
+	}
+}
+
+// applyScrollBtnPosition 按页签栏位置设置滚动导航按钮和页签列表按钮的大小和图标
+func (m *TTab) applyScrollBtnPosition() {
+	if m.scrollLeftBtn == nil {
+		return
+	}
+	if m.vertical() {
+		m.scrollLeftBtn.SetIconAsset("tab/scroll-up")
+		m.scrollRightBtn.SetIconAsset("tab/scroll-down")
+	} else {
+		m.scrollLeftBtn.SetIconAsset("tab/scroll-left")
+		m.scrollRightBtn.SetIconAsset("tab/scroll-right")
+	}
+	m.applyHints()
+	for _, button := range []*TButton{m.scrollLeftBtn, m.scrollRightBtn, m.overflowBtn} {
+		if m.vertical() {
+			button.SetBounds(button.Left(), button.Top(), m.buttonThickness(), scrollBtnWidth)
+		} else {
+			button.SetBounds(button.Left(), button.Top(), scrollBtnWidth, scrollBtnHeight)
+		}
+	}
+}
+
+// applyPosition 页签栏位置或文字方向变化后重新设置按钮和内容区, 滚动回到起点
+func (m *TTab) applyPosition() {
+	if !m.IsValid() {
+		return
+	}
+	m.drag = nil
+	for button := range m.slides {
+		delete(m.slides, button)
+	}
+	for _, page := range m.Pages() {
+		m.applyButtonPosition(page.button)
+		m.applyGroupMark(page)
+	}
+	for _, group := range m.Groups() {
+		m.applyButtonPosition(group.chip)
+	}
+	m.applyScrollBtnPosition()
+	for _, page := range m.Pages() {
+		m.layoutSheet(page)
+	}
+	m.scrollTarget = 0
+	m.setScrollOffset(0)
+	m.recalculatePosition()
+}
+
+// SetHints 设置滚动导航按钮和页签列表按钮的提示
+func (m *TTab) SetHints(hints TTabHints) {
+	m.lock.Lock()
+	m.hints = hints
+	m.lock.Unlock()
+	runOnMainThread(m.applyHints)
+}
+
+// Hints 返回滚动导航按钮和页签列表按钮的提示
+func (m *TTab) Hints() TTabHints {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.hints
+}
+
+// applyHints 按页签栏方向设置滚动导航按钮和页签列表按钮的提示, 在主线程执行
+func (m *TTab) applyHints() {
+	if m.scrollLeftBtn == nil || m.overflowBtn == nil {
+		return
+	}
+	hints := m.Hints()
+	if m.vertical() {
+		m.scrollLeftBtn.SetHint(hints.ScrollUp)
+		m.scrollRightBtn.SetHint(hints.ScrollDown)
+	} else {
+		m.scrollLeftBtn.SetHint(hints.ScrollLeft)
+		m.scrollRightBtn.SetHint(hints.ScrollRight)
+	}
+	m.overflowBtn.SetHint(hints.AllTabs)
+}
@@ -0,0 +1,134 @@
+package wg
+
+// SetMultiRow 设置页签超出页签栏时是否换行, 代替滚动, 默认滚动
+//
+//	多行时内容区随行数缩小, 滚动导航按钮隐藏, 页签不能拖动排序(可以拖出)
+func (m *TTab) SetMultiRow(enabled bool) {
+	runOnMainThread(func() {
+		m.lock.Lock()
+		changed := m.multiRow != enabled
+		m.multiRow = enabled
+		m.lock.Unlock()
+		if changed {
+			m.scrollTarget = 0
+			m.setScrollOffset(0)
+			m.recalculatePosition()
+		}
+	})
+}
+
+// MultiRow 返回页签超出页签栏时是否换行
+func (m *TTab) MultiRow() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.multiRow
+}
+
+// SetActiveRowAdjacent 设置多行时当前页所在行是否移到靠内容区的一侧, 其它行按顺序轮换(Windows 经典样式)
+//
+//	默认不移动, 各行位置保持不变
+func (m *TTab) SetActiveRowAdjacent(adjacent bool) {
+	m.lock.Lock()
+	m.activeRowAdjacent = adjacent
+	m.lock.Unlock()
+	m.RecalculatePosition()
+}
+
+// ActiveRowAdjacent 返回多行时当前页所在行是否移到靠内容区的一侧
+func (m *TTab) ActiveRowAdjacent() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.activeRowAdjacent
+}
+
+// RowCount 返回页签行数, 不换行时为 1
+func (m *TTab) RowCount() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.rows < 1 {
+		return 1
+	}
+	return m.rows
+}
+
+// setRows 设置行数, 行数变化时重新设置所有页的内容区, 在主线程执行
+func (m *TTab) setRows(rows int, pages []*TPage) {
+	m.lock.Lock()
+	changed := m.rows != rows
+	m.rows = rows
+	m.lock.Unlock()
+	if changed {
+		for _, page := range pages {
+			m.layoutSheet(page)
+		}
+	}
+}
+
+// layoutRows 多行排列页签和组标签, 每行从起点开始排列, 超出时换行, 在主线程执行
+func (m *TTab) layoutRows(pages []*TPage, items []tStripItem, margin int32) {
+	limit := m.stripLength() - m.overflowWidth()
+	var rows [][]tStripItem
+	var row []tStripItem
+	used := margin
+	for _, item := range items {
+		length := m.btnLen(item.button)
+		if len(row) > 0 && used+length+margin > limit {
+			rows = append(rows, row)
+			row, used = nil, margin
+		}
+		row = append(row, item)
+		used += length + margin
+	}
+	if len(row) > 0 || len(rows) == 0 {
+		rows = append(rows, row)
+	}
+	m.setRows(len(rows), pages)
+
+	// 显示位置 0 为页签栏外侧, len(rows)-1 靠内容区
+	shift := 0
+	if m.ActiveRowAdjacent() {
+		for i, row := range rows {
+			for _, item := range row {
+				if item.page != nil && item.page.Active() {
+					shift = len(rows) - 1 - i
+				}
+			}
+		}
+	}
+	thickness := m.rowThickness()
+	for i, row := range rows {
+		display := int32((i + shift) % len(rows))
+		pos := margin
+		for _, item := range row {
+			m.placeRow(item.button, display, thickness)
+			m.placeButton(item.button, pos)
+			pos += m.btnLen(item.button) + margin
+		}
+	}
+}
+
+// placeRow 设置按钮在第 display 行的厚度方向位置, 从页签栏外侧开始计算
+func (m *TTab) placeRow(button *TButton, display, thickness int32) {
+	switch m.position {
+	case TpBottom:
+		button.SetTop(m.Height() - (display+1)*thickness)
+	case TpLeft:
+		button.SetLeft(display * thickness)
+	case TpRight:
+		button.SetLeft(m.Width() - (display+1)*thickness)
+	default:
+		button.SetTop(display * thickness)
+	}
+}
+
+// pageActivated 激活页后更新最近使用顺序和焦点框, 多行并且当前页所在行靠内容区时重新排列行, 在主线程执行
+func (m *TTab) pageActivated(page *TPage) {
+	// 连续切换结束后才更新最近使用顺序
+	if m.cycle == nil {
+		m.touchMRU(page)
+	}
+	m.updateFocusRect()
+	if m.multiRow && m.ActiveRowAdjacent() {
+		m.recalculatePosition()
+	}
+}
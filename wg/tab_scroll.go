@@ -0,0 +1,252 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"time"
+)
+
+// 鼠标滚轮每格(WheelDelta 120)滚动的距离, 触摸板的滚动量较小, 按比例滚动
+var wheelScrollStep = int32(60)
+
+// TScrollButtonMode 滚动导航按钮的显示方式
+type TScrollButtonMode int32
+
+const (
+	SbmAlways      TScrollButtonMode = iota // 一直显示, 默认
+	SbmAutoHide                             // 页签没有超出页签栏时隐藏
+	SbmAutoDisable                          // 一直显示, 不能继续向该方向滚动时禁用
+)
+
+// SetScrollButtonMode 设置滚动导航按钮的显示方式
+func (m *TTab) SetScrollButtonMode(mode TScrollButtonMode) {
+	m.lock.Lock()
+	m.scrollBtnMode = mode
+	m.lock.Unlock()
+	m.RecalculatePosition()
+}
+
+// ScrollButtonMode 返回滚动导航按钮的显示方式
+func (m *TTab) ScrollButtonMode() TScrollButtonMode {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.scrollBtnMode
+}
+
+// SetSmoothScroll 设置鼠标滚轮和滚动到页签时是否平滑滚动, 默认平滑滚动
+//
+//	按住滚动导航按钮时一直是连续滚动
+func (m *TTab) SetSmoothScroll(smooth bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.noSmoothScroll = !smooth
+}
+
+// SmoothScroll 返回是否平滑滚动
+func (m *TTab) SmoothScroll() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return !m.noSmoothScroll
+}
+
+// SetScrollToActive 设置激活页时是否自动滚动页签栏, 使激活的页签完全可见, 默认滚动
+func (m *TTab) SetScrollToActive(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.noScrollToActive = !enabled
+}
+
+// ScrollToActive 返回激活页时是否自动滚动页签栏
+func (m *TTab) ScrollToActive() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return !m.noScrollToActive
+}
+
+// ScrollToPage 滚动页签栏使页签完全可见, 页签比页签区域宽时左对齐, 固定的页签一直可见
+func (m *TTab) ScrollToPage(page *TPage) {
+	runOnMainThread(func() {
+		if page.tab == m && m.IsValid() {
+			m.scrollIntoView(page)
+		}
+	})
+}
+
+// ScrollOffset 返回页签栏的滚动偏移, 没有滚动时为 0, 向右滚动后为负数
+func (m *TTab) ScrollOffset() int32 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.scrollOffset
+}
+
+// initStripWheel 在页签栏, 页签按钮和滚动导航按钮上使用鼠标滚轮滚动页签
+func (m *TTab) initStripWheel(control interface {
+	SetOnMouseWheel(lcl.TMouseWheelEvent)
+	SetOnMouseWheelHorz(lcl.TMouseWheelEvent)
+}, inStrip bool) {
+	wheel := func(delta int32, mousePos types.TPoint, handled *bool) {
+		// 页签栏下方是内容区, 内容区未处理的滚轮事件不滚动页签
+		if inStrip && !m.stripRect().PtInRect(mousePos) {
+			return
+		}
+		if m.minScrollOffset() == 0 {
+			return
+		}
+		*handled = true
+		m.scrollTo(m.scrollTarget+delta*wheelScrollStep/120, true)
+	}
+	control.SetOnMouseWheel(func(sender lcl.IObject, shift types.TShiftState, wheelDelta int32, mousePos types.TPoint, handled *bool) {
+		wheel(wheelDelta, mousePos, handled)
+	})
+	// 触摸板水平滑动, 向右滑动显示右侧页签
+	control.SetOnMouseWheelHorz(func(sender lcl.IObject, shift types.TShiftState, wheelDelta int32, mousePos types.TPoint, handled *bool) {
+		wheel(-wheelDelta, mousePos, handled)
+	})
+}
+
+// minScrollOffset 返回滚动偏移的最小值, 页签没有超出页签区域或多行时为 0
+func (m *TTab) minScrollOffset() int32 {
+	if m.multiRow {
+		return 0
+	}
+	m.lock.RLock()
+	content := m.contentWidth
+	m.lock.RUnlock()
+	if over := content - (m.stripEnd() - m.stripStart()); over > 0 {
+		return -over
+	}
+	return 0
+}
+
+// clampScroll 把滚动偏移限制在可以滚动的范围内
+func (m *TTab) clampScroll(offset int32) int32 {
+	if offset > 0 {
+		return 0
+	}
+	if min := m.minScrollOffset(); offset < min {
+		return min
+	}
+	return offset
+}
+
+// scrollBy 立即滚动 delta, 返回是否滚动, 在主线程执行
+func (m *TTab) scrollBy(delta int32) bool {
+	offset := m.ScrollOffset()
+	m.scrollTo(m.scrollTarget+delta, false)
+	return m.ScrollOffset() != offset
+}
+
+// scrollTo 滚动到偏移位置, animate 为 true 且平滑滚动时动画滚动, 在主线程执行
+func (m *TTab) scrollTo(offset int32, animate bool) {
+	m.scrollTarget = m.clampScroll(offset)
+	if animate && m.SmoothScroll() {
+		if !m.scrolling {
+			m.scrolling = true
+			m.scrollFrame()
+		}
+		return
+	}
+	m.setScrollOffset(m.scrollTarget)
+}
+
+// setScrollOffset 设置滚动偏移并重新计算位置, 在主线程执行
+func (m *TTab) setScrollOffset(offset int32) {
+	m.lock.Lock()
+	changed := m.scrollOffset != offset
+	m.scrollOffset = offset
+	m.lock.Unlock()
+	if changed {
+		m.recalculatePosition()
+	}
+}
+
+// scrollFrame 平滑滚动的一帧, 每帧移动剩余距离的一半, 在主线程执行
+func (m *TTab) scrollFrame() {
+	if !m.IsValid() {
+		m.scrolling = false
+		return
+	}
+	diff := m.scrollTarget - m.ScrollOffset()
+	if abs32(diff) <= 1 {
+		m.setScrollOffset(m.scrollTarget)
+		m.scrolling = false
+		return
+	}
+	m.setScrollOffset(m.ScrollOffset() + diff/2)
+	time.AfterFunc(fadeFrameInterval, func() {
+		lcl.RunOnMainThreadAsync(func(id uint32) {
+			m.scrollFrame()
+		})
+	})
+}
+
+// scrollIntoView 滚动页签栏使页签完全显示在页签区域内, 在主线程执行
+//
+//	页签比页签区域长时起点对齐, 按滚动结束后的位置计算
+func (m *TTab) scrollIntoView(page *TPage) {
+	if !page.button.Visible() || page.Pinned() {
+		return
+	}
+	start, end := m.stripStart(), m.stripEnd()
+	buttonStart := m.slotPos(page) + m.scrollTarget - m.ScrollOffset()
+	buttonEnd := buttonStart + m.btnLen(page.button)
+	var delta int32
+	if buttonStart < start || buttonEnd-buttonStart > end-start {
+		delta = start - buttonStart
+	} else if buttonEnd > end {
+		delta = end - buttonEnd
+	}
+	if delta != 0 {
+		m.scrollTo(m.scrollTarget+delta, true)
+	}
+}
+
+// scrollToActiveLater 布局更新后滚动到激活的页, 同一轮多次激活只滚动到最后一个, 在主线程执行
+func (m *TTab) scrollToActiveLater(page *TPage) {
+	if !m.ScrollToActive() {
+		return
+	}
+	pending := m.scrollPage != nil
+	m.scrollPage = page
+	if pending {
+		return
+	}
+	lcl.RunOnMainThreadAsync(func(id uint32) {
+		page := m.scrollPage
+		m.scrollPage = nil
+		if m.IsValid() && page.tab == m && page.IsValid() {
+			m.scrollIntoView(page)
+		}
+	})
+}
+
+// updateScrollBtnVisible 按显示方式和 EnableScrollButton 设置滚动导航按钮是否显示, 在主线程执行
+func (m *TTab) updateScrollBtnVisible() {
+	m.lock.RLock()
+	mode, hidden, content := m.scrollBtnMode, m.scrollBtnHidden, m.contentWidth
+	m.lock.RUnlock()
+	visible := !hidden && !m.multiRow
+	if mode == SbmAutoHide {
+		visible = visible && content > m.stripLength()-m.overflowWidth()-m.pinnedWidth
+	}
+	for _, button := range []*TButton{m.scrollLeftBtn, m.scrollRightBtn} {
+		if button != nil && button.Visible() != visible {
+			button.SetVisible(visible)
+		}
+	}
+}
+
+// updateScrollBtnEnabled 自动禁用方式时禁用不能继续滚动方向的按钮, 在主线程执行
+func (m *TTab) updateScrollBtnEnabled(offset, minOffset int32) {
+	autoDisable := m.ScrollButtonMode() == SbmAutoDisable
+	if button := m.scrollLeftBtn; button != nil {
+		if disable := autoDisable && offset >= 0; button.Disable() != disable {
+			button.SetDisable(disable)
+		}
+	}
+	if button := m.scrollRightBtn; button != nil {
+		if disable := autoDisable && offset <= minOffset; button.Disable() != disable {
+			button.SetDisable(disable)
+		}
+	}
+}
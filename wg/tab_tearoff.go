@@ -0,0 +1,307 @@
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+)
+
+// 拖出页签: 垂直方向离开页签栏超过该距离后松开时拖出
+const tearOffDistance = int32(20)
+
+// 可以接收拖入页签的 TTab, 只在主线程访问
+var dropTabs []*TTab
+
+// TTearOffEvent 页签拖出到新窗口之前触发, cancel 设置为 true 时取消, 页签回到原位置
+//
+//	screenPos: 松开鼠标的屏幕坐标
+type TTearOffEvent func(sender lcl.IObject, page *TPage, screenPos types.TPoint, cancel *bool)
+
+// TTabWindowEvent 拖出页签创建窗口后, 页移入和窗口显示之前触发, 可以设置窗口标题, 大小, 图标等
+//
+//	tab 为窗口中新建的 TTab, 已复制原 TTab 的设置和事件
+type TTabWindowEvent func(sender lcl.IObject, page *TPage, form lcl.IForm, tab *TTab)
+
+// TPageDropEvent 页签拖入另一个 TTab 之前在目标 TTab 上触发, cancel 设置为 true 时取消
+//
+//	sender 为目标 TTab, source 为页原来所在的 TTab, index 为插入位置
+type TPageDropEvent func(sender lcl.IObject, page *TPage, source *TTab, index int, cancel *bool)
+
+// SetTearOff 设置是否可以把页签拖出页签栏, 拖到其它 TTab 或新窗口, 默认不可以
+func (m *TTab) SetTearOff(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.tearOff = enabled
+}
+
+// TearOff 返回是否可以把页签拖出页签栏
+func (m *TTab) TearOff() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.tearOff
+}
+
+// SetAcceptDrop 设置是否接收其它 TTab 拖入的页签, 默认不接收
+func (m *TTab) SetAcceptDrop(accept bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.acceptDrop = accept
+}
+
+// AcceptDrop 返回是否接收其它 TTab 拖入的页签
+func (m *TTab) AcceptDrop() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.acceptDrop
+}
+
+// SetOnTearOff 设置页签拖出到新窗口之前的事件
+func (m *TTab) SetOnTearOff(fn TTearOffEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onTearOff = fn
+}
+
+// SetOnTabWindow 设置拖出页签创建窗口后的事件
+func (m *TTab) SetOnTabWindow(fn TTabWindowEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onTabWindow = fn
+}
+
+// SetOnPageDrop 设置其它 TTab 的页签拖入之前的事件
+func (m *TTab) SetOnPageDrop(fn TPageDropEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onPageDrop = fn
+}
+
+// Window 返回拖出页签时创建的窗口, 其它 TTab 返回 nil
+//
+//	窗口中的页全部移走或关闭后窗口自动关闭
+func (m *TTab) Window() lcl.IForm {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.window
+}
+
+// MovePage 把页移动到 index 位置, index 小于 0 或超出时添加到最后
+//
+//	页可以属于其它 TTab, 按钮和内容区连同其中的控件一起移动(重新设置父控件和所有者), 不重新创建
+//	移动后页成为当前页
+func (m *TTab) MovePage(page *TPage, index int) {
+	runOnMainThread(func() {
+		m.movePage(page, index)
+	})
+}
+
+// movePage 移动页, 在主线程执行
+func (m *TTab) movePage(page *TPage, index int) {
+	source := page.tab
+	if source == nil || !m.IsValid() {
+		return
+	}
+	if source != m {
+		source.removePage(page)
+		// 转移所有者, 原 TTab 释放时不释放页
+		for _, c := range []lcl.IComponent{page.button, page.ICustomPanel, page.tabSheet} {
+			source.RemoveComponent(c)
+			m.InsertComponent(c)
+		}
+		page.tab = m
+		page.button.tracker = m
+		page.button.setOpacity(m.Opacity())
+		m.initStripWheel(page.button, false)
+		m.applyButtonPosition(page.button)
+		page.button.SetParent(m)
+		page.ICustomPanel.SetParent(m)
+		m.layoutSheet(page)
+	}
+	m.lock.Lock()
+	pages := make([]*TPage, 0, len(m.pages)+1)
+	for _, p := range m.pages {
+		if p != page {
+			pages = append(pages, p)
+		}
+	}
+	if index < 0 || index > len(pages) {
+		index = len(pages)
+	}
+	// 固定的页签在其它页签之前
+	if pinned := m.pinnedCount(pages); page.Pinned() && index > pinned || !page.Pinned() && index < pinned {
+		index = pinned
+	}
+	pages = append(pages[:index:index], append([]*TPage{page}, pages[index:]...)...)
+	m.pages = pages
+	m.lock.Unlock()
+	page.applyTabPopupMenu()
+	// 放在组的成员之间时加入该组
+	m.regroup(page)
+	m.HideAllActivated()
+	page.SetActive(true)
+	m.recalculatePosition()
+}
+
+// registerDropTab 记录 TTab, 拖出页签时查找目标, 释放时移除
+//
+//	LCL 的销毁事件由 TTab 内部使用, 用户的销毁事件通过 TTab.SetOnDestroy 在移除之后调用
+func registerDropTab(tab *TTab) {
+	dropTabs = append(dropTabs, tab)
+	tab.ICustomPanel.SetOnDestroy(func() {
+		for i, t := range dropTabs {
+			if t == tab {
+				dropTabs = append(dropTabs[:i:i], dropTabs[i+1:]...)
+				break
+			}
+		}
+		tab.lock.RLock()
+		fn := tab.onDestroy
+		tab.lock.RUnlock()
+		if fn != nil {
+			fn()
+		}
+	})
+}
+
+// SetOnDestroy 设置销毁事件, 不会替换 TTab 内部的销毁处理
+func (m *TTab) SetOnDestroy(fn func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onDestroy = fn
+}
+
+// findDropTab 返回屏幕坐标所在页签栏的 TTab, 不包括 exclude
+func findDropTab(pos types.TPoint, exclude *TTab) *TTab {
+	for _, t := range dropTabs {
+		if t == exclude || !t.IsValid() || !t.Visible() || !t.AcceptDrop() {
+			continue
+		}
+		if t.stripRect().PtInRect(t.ScreenToClient(pos)) {
+			return t
+		}
+	}
+	return nil
+}
+
+// dropIndex 返回屏幕坐标在页签栏中对应的插入位置
+func (m *TTab) dropIndex(pos types.TPoint) int {
+	pt := m.ScreenToClient(pos)
+	mouse, _ := m.mainCoord(pt.X, pt.Y)
+	pages := m.Pages()
+	for i, page := range pages {
+		if page.button.Visible() && mouse < m.btnPos(page.button)+m.btnLen(page.button)/2 {
+			return i
+		}
+	}
+	return len(pages)
+}
+
+// dropPage 在屏幕坐标 pos 松开拖出的页签, 拖入其它 TTab 或拖出到新窗口, 在主线程执行
+func (m *TTab) dropPage(page *TPage, pos types.TPoint, grab int32) {
+	if page.tab != m {
+		return
+	}
+	if target := findDropTab(pos, m); target != nil {
+		index := target.dropIndex(pos)
+		cancel := false
+		target.lock.RLock()
+		fn := target.onPageDrop
+		target.lock.RUnlock()
+		if fn != nil {
+			fn(target, page, m, index, &cancel)
+		}
+		if !cancel {
+			target.movePage(page, index)
+		}
+		return
+	}
+	// 拖出窗口中只有一页时拖出没有意义
+	if m.Window() != nil && len(m.Pages()) == 1 {
+		return
+	}
+	m.tearOffPage(page, pos, grab)
+}
+
+// tearOffPage 创建新窗口并把页移入窗口中的 TTab, 在主线程执行
+func (m *TTab) tearOffPage(page *TPage, pos types.TPoint, grab int32) {
+	m.lock.RLock()
+	onTearOff, onTabWindow := m.onTearOff, m.onTabWindow
+	m.lock.RUnlock()
+	cancel := false
+	if onTearOff != nil {
+		onTearOff(m, page, pos, &cancel)
+	}
+	if cancel {
+		return
+	}
+	form := lcl.NewForm(nil)
+	form.SetCaption(page.button.Caption())
+	// 新窗口中的页签位于鼠标下
+	left, top := pos.X-grab, pos.Y-page.button.Height()/2
+	if m.vertical() {
+		left, top = pos.X-page.button.Width()/2, pos.Y-grab
+	}
+	form.SetBounds(left, top, m.Width(), m.Height())
+	form.SetOnClose(func(sender lcl.IObject, closeAction *types.TCloseAction) {
+		*closeAction = types.CaFree
+	})
+	tab := NewTab(form)
+	// 焦点在页内容中时也处理页签快捷键
+	form.SetKeyPreview(true)
+	form.SetOnKeyDown(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		tab.handleKeyDown(key, shift)
+	})
+	form.SetOnKeyUp(func(sender lcl.IObject, key *uint16, shift types.TShiftState) {
+		tab.handleKeyUp(key, shift)
+	})
+	tab.SetParent(form)
+	tab.SetAlign(types.AlClient)
+	tab.copySettings(m)
+	tab.overflowBtn.SetVisible(m.overflowBtn.Visible())
+	tab.applyPosition()
+	tab.lock.Lock()
+	tab.window = form
+	tab.lock.Unlock()
+	if onTabWindow != nil {
+		onTabWindow(m, page, form, tab)
+	}
+	tab.movePage(page, 0)
+	form.Show()
+}
+
+// copySettings 复制页签栏设置和事件到拖出窗口中的 TTab
+func (m *TTab) copySettings(source *TTab) {
+	source.lock.RLock()
+	defer source.lock.RUnlock()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.Margin = source.Margin
+	m.tearOff = source.tearOff
+	m.acceptDrop = source.acceptDrop
+	m.fixedOrder = source.fixedOrder
+	m.overflowShowHidden = source.overflowShowHidden
+	m.scrollBtnMode = source.scrollBtnMode
+	m.scrollBtnHidden = source.scrollBtnHidden
+	m.hints = source.hints
+	m.noSmoothScroll = source.noSmoothScroll
+	m.noScrollToActive = source.noScrollToActive
+	m.position = source.position
+	m.rotatedText = source.rotatedText
+	m.stripWidth = source.stripWidth
+	m.multiRow = source.multiRow
+	m.activeRowAdjacent = source.activeRowAdjacent
+	m.shortcuts = make(map[TTabCommand][]TTabShortcut, len(source.shortcuts))
+	for command, shortcuts := range source.shortcuts {
+		m.shortcuts[command] = append([]TTabShortcut(nil), shortcuts...)
+	}
+	m.mruOrder = source.mruOrder
+	m.switcher = source.switcher
+	m.onPinChange = source.onPinChange
+	m.onPageGroup = source.onPageGroup
+	m.tabPopupMenu = source.tabPopupMenu
+	m.onTabContextMenu = source.onTabContextMenu
+	m.onReorder = source.onReorder
+	m.onChange = source.onChange
+	m.onTearOff = source.onTearOff
+	m.onTabWindow = source.onTabWindow
+	m.onPageDrop = source.onPageDrop
+}
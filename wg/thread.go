@@ -0,0 +1,54 @@
+package wg
+
+import (
+	"bytes"
+	"github.com/energye/lcl/lcl"
+	"runtime"
+	"strconv"
+)
+
+// 主线程(UI 线程)所在的 goroutine
+//
+//	包初始化总是在 main goroutine 中执行, LCL 也只能在这个 goroutine 中初始化和运行,
+//	所以在 init 中记录, 与第一个控件在哪里创建无关.
+//	LCL 的事件回调也在同一个 goroutine 中执行.
+//	无法取得 goroutine id 时为 0, 所有调用都投递到主线程执行
+var mainGoroutineID = goroutineID()
+
+// isMainThread 当前是否在主线程
+func isMainThread() bool {
+	return mainGoroutineID != 0 && goroutineID() == mainGoroutineID
+}
+
+// runOnMainThread 在主线程执行 fn
+//
+//	当前在主线程时直接执行, 否则投递到主线程异步执行
+func runOnMainThread(fn func()) {
+	if isMainThread() {
+		fn()
+		return
+	}
+	lcl.RunOnMainThreadAsync(func(id uint32) {
+		fn()
+	})
+}
+
+// goroutineID 返回当前 goroutine id, 解析失败时返回 0
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	// 格式: goroutine 1 [running]:
+	s, ok := bytes.CutPrefix(buf[:n], []byte("goroutine "))
+	if !ok {
+		return 0
+	}
+	i := bytes.IndexByte(s, ' ')
+	if i <= 0 {
+		return 0
+	}
+	id, err := strconv.ParseUint(string(s[:i]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
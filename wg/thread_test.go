@@ -0,0 +1,166 @@
+//go:build lcl
+
+package wg
+
+import (
+	"github.com/energye/lcl/lcl"
+	"github.com/energye/lcl/types"
+	"github.com/energye/lcl/types/colors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 用 go test -race 运行, 检查其它 goroutine 的设置与主线程绘制之间的数据竞争
+
+// 并发设置的次数和绘制次数
+const (
+	raceSetters = 4
+	raceRounds  = 200
+)
+
+func TestMainThread(t *testing.T) {
+	if isMainThread() {
+		t.Fatal("test goroutine reported as main thread")
+	}
+	onMain(func() {
+		if !isMainThread() {
+			t.Error("main goroutine not reported as main thread")
+		}
+	})
+	// 其它 goroutine 的调用投递到主线程
+	done := make(chan bool, 1)
+	runOnMainThread(func() {
+		done <- isMainThread()
+	})
+	select {
+	case onMain := <-done:
+		if !onMain {
+			t.Error("runOnMainThread did not run on main thread")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runOnMainThread did not run")
+	}
+}
+
+// raceWithPaint 在多个 goroutine 中调用 set, 同时在主线程调用 paint
+func raceWithPaint(set func(i int), paint func()) {
+	var wg sync.WaitGroup
+	for g := 0; g < raceSetters; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < raceRounds; i++ {
+				set(g*raceRounds + i)
+			}
+		}(g)
+	}
+	for i := 0; i < raceRounds; i++ {
+		onMain(paint)
+	}
+	wg.Wait()
+	// 等待投递到主线程的设置执行完
+	onMain(lcl.Application.ProcessMessages)
+}
+
+// newPaintTarget 创建按钮和离屏绘制目标
+func newPaintTarget(t *testing.T) (lcl.IForm, lcl.IBitmap) {
+	var form lcl.IForm
+	var bitMap lcl.IBitmap
+	onMain(func() {
+		form = lcl.NewForm(nil)
+		bitMap = lcl.NewBitmap()
+		bitMap.SetPixelFormat(types.Pf32bit)
+		bitMap.SetSize(160, 48)
+	})
+	t.Cleanup(func() {
+		onMain(func() {
+			bitMap.Free()
+			form.Free()
+		})
+	})
+	return form, bitMap
+}
+
+func TestButtonSettersRacePaint(t *testing.T) {
+	form, bitMap := newPaintTarget(t)
+	var button *TButton
+	onMain(func() {
+		button = NewButton(form)
+		button.SetParent(form)
+		button.SetBounds(0, 0, 160, 48)
+	})
+	rect := types.TRect{Right: 160, Bottom: 48}
+	corners := []TRoundedCorners{types.NewSet(RcLeftTop), types.NewSet(RcLeftTop, RcRightBottom), types.NewSet()}
+	raceWithPaint(func(i int) {
+		c := colors.RGBToColor(byte(i), byte(i*3), byte(i*7))
+		switch i % 9 {
+		case 0:
+			button.SetColor(c)
+		case 1:
+			button.SetRoundedCorners(corners[i%len(corners)])
+		case 2:
+			button.SetTextAlign(TextAlign(i % 3))
+		case 3:
+			button.SetTextOffset(int32(i%5), int32(i%3))
+		case 4:
+			button.SetText("button " + string(rune('a'+i%26)))
+		case 5:
+			button.SetRadius(int32(i % 12))
+		case 6:
+			button.SetAlpha(byte(i))
+		case 7:
+			button.SetBorderColor(BbdNone, c)
+		case 8:
+			button.SetDisable(i%2 == 0)
+		}
+	}, func() {
+		button.drawRoundedGradientButton(bitMap.Canvas(), rect)
+		button.drawTranslucent(bitMap.Canvas(), rect, 128)
+	})
+}
+
+func TestInputSettersRacePaint(t *testing.T) {
+	form, bitMap := newPaintTarget(t)
+	var input *TInput
+	onMain(func() {
+		input = NewInput(form)
+		input.SetParent(form)
+	})
+	rect := types.TRect{Right: 160, Bottom: 48}
+	raceWithPaint(func(i int) {
+		switch i % 4 {
+		case 0:
+			input.SetText(time.Duration(i).String())
+		case 1:
+			input.SetTextColor(colors.RGBToColor(byte(i), 0, 0))
+		case 2:
+			input.SetBackgroundColor(colors.RGBToColor(0, byte(i), 0))
+		case 3:
+			input.SetOpacity(byte(i))
+		}
+	}, func() {
+		input.drawText(bitMap.Canvas())
+		input.drawTranslucent(bitMap.Canvas(), rect, 128)
+	})
+}
+
+func TestTabSettersRaceLayout(t *testing.T) {
+	form, _ := newPaintTarget(t)
+	var tab *TTab
+	onMain(func() {
+		tab = NewTab(form)
+		tab.SetParent(form)
+		tab.SetBounds(0, 0, 400, 40)
+		for i := 0; i < 5; i++ {
+			tab.NewPage().button.SetText("page")
+		}
+	})
+	raceWithPaint(func(i int) {
+		if i%2 == 0 {
+			tab.SetMargin(int32(i % 6))
+		} else {
+			tab.SetOpacity(byte(i))
+		}
+	}, tab.recalculatePosition)
+}